@@ -0,0 +1,42 @@
+// Package address normalizes the inbox names and addresses given
+// by users, an inbox can be named by its login or by its address
+// on any of the yopmail alias domains
+package address
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DefaultDomain is the domain of the address of an inbox named by its login
+const DefaultDomain = "yopmail.com"
+
+// DomainError is returned when an address is not on a yopmail domain
+type DomainError struct {
+	Domain string
+}
+
+func (e *DomainError) Error() string {
+	return fmt.Sprintf(`"%s" is not a yopmail domain`, e.Domain)
+}
+
+// Normalize returns the login and the address of an inbox from its
+// login or from its address on any of the domains
+func Normalize(inbox string, domains []string) (login string, address string, err error) {
+	// Providing an uppercased email triggers a panic.
+	// In the web interface there is a redirection to
+	// the inbox with the address lowercased so we mimic
+	// this behaviour
+	login, domain, found := strings.Cut(strings.ToLower(strings.TrimSpace(inbox)), "@")
+	if login == "" {
+		return "", "", fmt.Errorf(`inbox "%s" is not a valid inbox name`, inbox)
+	}
+	if !found {
+		domain = DefaultDomain
+	}
+	if !slices.Contains(domains, domain) {
+		return "", "", &DomainError{Domain: domain}
+	}
+	return login, login + "@" + domain, nil
+}
@@ -0,0 +1,61 @@
+package address
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	type scenario struct {
+		name            string
+		inbox           string
+		loginExpected   string
+		addressExpected string
+		err             error
+	}
+
+	domains := []string{"yopmail.com", "yopmail.fr"}
+	scenarios := []scenario{
+		{
+			name:            "uppercased login",
+			inbox:           " TeSt ",
+			loginExpected:   "test",
+			addressExpected: "test@yopmail.com",
+		},
+		{
+			name:            "address on the default domain",
+			inbox:           "Test@YOPmail.com",
+			loginExpected:   "test",
+			addressExpected: "test@yopmail.com",
+		},
+		{
+			name:            "address on an alias domain",
+			inbox:           "test@yopmail.fr",
+			loginExpected:   "test",
+			addressExpected: "test@yopmail.fr",
+		},
+		{
+			name:  "address on a foreign domain",
+			inbox: "test@example.com",
+			err:   &DomainError{Domain: "example.com"},
+		},
+		{
+			name:  "empty login",
+			inbox: "@yopmail.com",
+			err:   errors.New(`inbox "@yopmail.com" is not a valid inbox name`),
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			login, address, err := Normalize(scenario.inbox, domains)
+			assert.Equal(t, scenario.err, err)
+			assert.Equal(t, scenario.loginExpected, login)
+			assert.Equal(t, scenario.addressExpected, address)
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// CaptchaPolicy defines how the client waits for a CAPTCHA to be lifted
+// instead of failing with ErrCaptcha, the zero value disables the wait
+type CaptchaPolicy struct {
+	// Cooldown is the delay before probing yopmail for the first
+	// time, the wait is enabled when it is greater than 0
+	Cooldown time.Duration
+	// ProbeInterval is the delay between two probes,
+	// it defaults to the cooldown
+	ProbeInterval time.Duration
+	// Deadline caps the overall time spent waiting, 0 waits
+	// until the context is done
+	Deadline time.Duration
+	// Progress receives a line on every step of the wait, may be nil
+	Progress io.Writer
+}
+
+// captchaWaiter tracks the time spent waiting for the CAPTCHA
+// to be lifted, the deadline starts with the first wait
+type captchaWaiter struct {
+	mu     sync.Mutex
+	policy CaptchaPolicy
+	until  time.Time
+	now    func() time.Time
+}
+
+func newCaptchaWaiter(policy CaptchaPolicy) *captchaWaiter {
+	if policy.Cooldown <= 0 {
+		return nil
+	}
+	if policy.ProbeInterval <= 0 {
+		policy.ProbeInterval = policy.Cooldown
+	}
+	return &captchaWaiter{policy: policy, now: time.Now}
+}
+
+// context returns a context canceled when the deadline is reached
+func (w *captchaWaiter) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.policy.Deadline <= 0 {
+		return context.WithCancel(ctx)
+	}
+	if w.until.IsZero() {
+		w.until = w.now().Add(w.policy.Deadline)
+	}
+	return context.WithDeadline(ctx, w.until)
+}
+
+func (w *captchaWaiter) notify(format string, args ...any) {
+	if w.policy.Progress != nil {
+		fmt.Fprintf(w.policy.Progress, format+"\n", args...)
+	}
+}
+
+// waitCAPTCHA pauses the interrupted operation and probes the
+// inbox until yopmail stops requiring a CAPTCHA, ErrCaptcha is
+// returned when no wait is configured or when the deadline is reached
+func (c Client[M]) waitCAPTCHA(ctx context.Context, identifier string) error {
+	w := c.browser.captcha
+	if w == nil {
+		return ErrCaptcha
+	}
+	waitCtx, cancel := w.context(ctx)
+	defer cancel()
+	w.notify("CAPTCHA detected, waiting %s before probing yopmail", w.policy.Cooldown)
+	delay := w.policy.Cooldown
+	// a CAPTCHA is also served to expired session tokens, they are
+	// refreshed on the first rejected probe only to keep probes cheap
+	refreshed := false
+	for probe := 1; ; probe++ {
+		select {
+		case <-waitCtx.Done():
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			w.notify("CAPTCHA still active, giving up after %s", w.policy.Deadline)
+			return fmt.Errorf("deadline of %s reached : %w", w.policy.Deadline, ErrCaptcha)
+		case <-time.After(delay):
+		}
+		lifted, err := c.probeCAPTCHA(waitCtx, identifier)
+		if err == nil && !lifted && !refreshed {
+			refreshed = true
+			if err = c.bootstrap(waitCtx); err == nil {
+				lifted, err = c.probeCAPTCHA(waitCtx, identifier)
+			}
+		}
+		if lifted {
+			w.notify("CAPTCHA lifted, resuming")
+			return nil
+		}
+		delay = w.policy.ProbeInterval
+		switch {
+		case err != nil && waitCtx.Err() == nil:
+			w.notify("CAPTCHA probe %d failed: %s, probing again in %s", probe, err, delay)
+		case err == nil:
+			w.notify("CAPTCHA still active (probe %d), probing again in %s", probe, delay)
+		}
+	}
+}
+
+// probeCAPTCHA fetches the first page of the inbox with the
+// current session tokens, the cheapest request that yopmail
+// guards with a CAPTCHA
+func (c Client[M]) probeCAPTCHA(ctx context.Context, identifier string) (bool, error) {
+	u, err := c.decorateURL("inbox?d=&ctrl=&scrl=&spam=true&ad=0&r_c=&id=", false, map[string]string{"login": identifier, "p": strconv.Itoa(1)})
+	if err != nil {
+		return false, err
+	}
+	if err := c.populateCookieFromAccount(identifier); err != nil {
+		return false, err
+	}
+	content, err := c.browser.fetch(ctx, "GET", u, map[string]string{}, nil)
+	if err != nil {
+		return false, err
+	}
+	if err := checkInboxCAPTCHA(content.String()); errors.Is(err, ErrCaptcha) {
+		return false, nil
+	}
+	return true, nil
+}
@@ -0,0 +1,169 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitCAPTCHA(t *testing.T) {
+	type scenario struct {
+		name   string
+		policy CaptchaPolicy
+		setup  func()
+		run    func(context.Context, Client[MailHTMLDoc]) error
+		test   func(output string, err error)
+	}
+
+	inboxURL := refURL + "/en/inbox?ad=0&ctrl=&d=&id=&login=box1&p=1&r_c=&scrl=&spam=true&v=3.1&yj=ytest&yp=yptest"
+	mailURL := refURL + "/en/mail?b=box1&id=mABCDEFGH"
+	// blockedFor answers with a CAPTCHA to the first requests
+	blockedFor := func(requests int, content string, captcha string) httpmock.Responder {
+		calls := 0
+		return func(r *http.Request) (*http.Response, error) {
+			calls++
+			if calls <= requests {
+				return httpmock.NewStringResponse(200, captcha), nil
+			}
+			return httpmock.NewStringResponse(200, content), nil
+		}
+	}
+	inbox := "w.finrmail(25,2,1,0,0,'alt.zk-4nyqp5l','')"
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	for _, s := range []scenario{
+		{
+			"wait disabled",
+			CaptchaPolicy{},
+			func() {
+				httpmock.RegisterResponder("GET", inboxURL, blockedFor(1, inbox, ""))
+			},
+			func(ctx context.Context, c Client[MailHTMLDoc]) error {
+				_, err := c.GetMailsPage(ctx, "box1", 1)
+				return err
+			},
+			func(output string, err error) {
+				assert.ErrorIs(t, err, ErrCaptcha)
+				assert.Equal(t, 1, httpmock.GetCallCountInfo()["GET "+inboxURL])
+			},
+		},
+		{
+			"inbox resumed once the CAPTCHA is lifted",
+			CaptchaPolicy{Cooldown: time.Millisecond, ProbeInterval: time.Millisecond},
+			func() {
+				httpmock.RegisterResponder("GET", inboxURL, blockedFor(4, inbox, ""))
+			},
+			func(ctx context.Context, c Client[MailHTMLDoc]) error {
+				_, err := c.GetMailsPage(ctx, "box1", 1)
+				return err
+			},
+			func(output string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, 6, httpmock.GetCallCountInfo()["GET "+inboxURL])
+				// the session is bootstrapped again on the first rejected probe only
+				assert.Equal(t, 2, httpmock.GetCallCountInfo()["GET "+refURL])
+				assert.Equal(t, `CAPTCHA detected, waiting 1ms before probing yopmail
+CAPTCHA still active (probe 1), probing again in 1ms
+CAPTCHA still active (probe 2), probing again in 1ms
+CAPTCHA lifted, resuming
+`, output)
+			},
+		},
+		{
+			"probe failure",
+			CaptchaPolicy{Cooldown: time.Millisecond, ProbeInterval: time.Millisecond},
+			func() {
+				calls := 0
+				httpmock.RegisterResponder("GET", inboxURL, func(r *http.Request) (*http.Response, error) {
+					calls++
+					switch calls {
+					case 1:
+						return httpmock.NewStringResponse(200, ""), nil
+					case 2:
+						return httpmock.NewStringResponse(500, ""), nil
+					}
+					return httpmock.NewStringResponse(200, inbox), nil
+				})
+			},
+			func(ctx context.Context, c Client[MailHTMLDoc]) error {
+				_, err := c.GetMailsPage(ctx, "box1", 1)
+				return err
+			},
+			func(output string, err error) {
+				assert.NoError(t, err)
+				assert.Contains(t, output, "CAPTCHA probe 1 failed: ")
+				assert.Contains(t, output, "request failed with error code 500 and body , probing again in 1ms\nCAPTCHA lifted, resuming\n")
+			},
+		},
+		{
+			"mail resumed once the CAPTCHA is lifted",
+			CaptchaPolicy{Cooldown: time.Millisecond, ProbeInterval: time.Millisecond},
+			func() {
+				httpmock.RegisterResponder("GET", inboxURL, httpmock.NewStringResponder(200, inbox))
+				httpmock.RegisterResponder("GET", mailURL, blockedFor(1, "<div id='mail'></div>", "window.showRc()"))
+			},
+			func(ctx context.Context, c Client[MailHTMLDoc]) error {
+				_, err := c.GetMailPage(ctx, "box1", "ABCDEFGH")
+				return err
+			},
+			func(output string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, 2, httpmock.GetCallCountInfo()["GET "+mailURL])
+				assert.Equal(t, 1, httpmock.GetCallCountInfo()["GET "+inboxURL])
+			},
+		},
+		{
+			"deadline reached",
+			CaptchaPolicy{Cooldown: time.Millisecond, ProbeInterval: 5 * time.Millisecond, Deadline: 30 * time.Millisecond},
+			func() {
+				httpmock.RegisterResponder("GET", inboxURL, httpmock.NewStringResponder(200, ""))
+			},
+			func(ctx context.Context, c Client[MailHTMLDoc]) error {
+				_, err := c.GetMailsPage(ctx, "box1", 1)
+				return err
+			},
+			func(output string, err error) {
+				assert.ErrorIs(t, err, ErrCaptcha)
+				assert.EqualError(t, err, "deadline of 30ms reached : failure when trying to access content: a CAPTCHA is probably activated, look to the web interface")
+				assert.Contains(t, output, "CAPTCHA still active, giving up after 30ms\n")
+			},
+		},
+		{
+			"context canceled",
+			CaptchaPolicy{Cooldown: time.Hour},
+			func() {
+				httpmock.RegisterResponder("GET", inboxURL, httpmock.NewStringResponder(200, ""))
+			},
+			func(ctx context.Context, c Client[MailHTMLDoc]) error {
+				ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+				defer cancel()
+				_, err := c.GetMailsPage(ctx, "box1", 1)
+				return err
+			},
+			func(output string, err error) {
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+			},
+		},
+	} {
+		t.Run(s.name, func(t *testing.T) {
+			mockYopmailSetup()
+			s.setup()
+
+			var output bytes.Buffer
+			s.policy.Progress = &output
+			c, err := New[MailHTMLDoc](context.Background(), Config{Captcha: s.policy})
+			assert.NoError(t, err)
+
+			err = s.run(context.Background(), c)
+			s.test(output.String(), err)
+			httpmock.Reset()
+		})
+	}
+}
@@ -2,11 +2,12 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
-	"net/http/httputil"
 	"net/url"
 	"os"
 	"regexp"
@@ -19,8 +20,6 @@ import (
 	"golang.org/x/net/http/httpproxy"
 )
 
-var ErrCaptcha = errors.New("failure when trying to access content: a CAPTCHA is probably activated, look to the web interface")
-
 const refURL = "https://yopmail.com"
 const defaultRequestTimeout = 10
 const defaultUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/87.0.4280.88 Safari/537.36"
@@ -44,131 +43,322 @@ type MailTextDoc goquery.Document
 
 // Client provides a high level interface to abstract yopmail data fetching
 type Client[M MailDoc] struct {
-	browser    *browser
+	browser     *browser
+	session     *session
+	baseURL     string
+	sessionFile string
+	location    *time.Location
+}
+
+// session holds the values scraped from yopmail that must be
+// sent along every inbox request, they are fetched once and
+// reused until yopmail rejects them
+type session struct {
 	apiVersion string
+	yp         string
+	yj         string
+	// fresh is true until the tokens have been used by a request
+	fresh bool
+}
+
+// Config customizes the behaviour of a client
+type Config struct {
+	// EnableDebugMode logs all requests and responses on stderr
+	EnableDebugMode bool
+	// Logger receives the debug records instead of stderr
+	Logger *slog.Logger
+	// HAR records all the HTTP traffic when set
+	HAR *HARRecorder
+	// Retry defines how failed requests are retried,
+	// the zero value disables retries
+	Retry RetryPolicy
+	// RateLimit paces the requests sent to yopmail,
+	// the zero value disables the limiter
+	RateLimit RateLimit
+	// Captcha waits for a CAPTCHA to be lifted and resumes the
+	// operation, the zero value fails immediately with ErrCaptcha
+	Captcha CaptchaPolicy
+	// BaseURL replaces the yopmail URL, to target a fake server
+	// for instance, it must not end with a slash
+	BaseURL string
+	// SessionFile persists the session tokens and the cookies
+	// between two runs, an empty value keeps them in memory
+	SessionFile string
+	// Location is the timezone of the dates displayed in the
+	// inboxes, the yopmail timezone is used when it is nil
+	Location *time.Location
 }
 
 // New creates a new client
-func New[M MailDoc](enableDebugMode bool) (Client[M], error) {
-	browser := newBrowser(enableDebugMode)
-	c, err := browser.fetch("GET", refURL, map[string]string{}, nil)
-	if err != nil {
-		return Client[M]{}, err
+func New[M MailDoc](ctx context.Context, config Config) (Client[M], error) {
+	c := Client[M]{browser: newBrowser(config), session: &session{}, baseURL: normalizeBaseURL(config.BaseURL), sessionFile: config.SessionFile, location: config.Location}
+	if c.sessionFile != "" {
+		sessions, err := LoadSessions(c.sessionFile)
+		if err != nil {
+			return Client[M]{}, err
+		}
+		s := sessions[c.baseURL]
+		c.browser.jar.load(s.Cookies)
+		if s.APIVersion != "" && s.YP != "" && s.YJ != "" {
+			*c.session = session{apiVersion: s.APIVersion, yp: s.YP, yj: s.YJ}
+			return c, nil
+		}
 	}
-	apiVersion, err := parseApiVersion(c.String())
-	if err != nil {
+	if err := c.bootstrap(ctx); err != nil {
 		return Client[M]{}, err
 	}
-	return Client[M]{apiVersion: apiVersion, browser: browser}, nil
+	return c, nil
 }
 
-// GetMailsPage fetches all html pages containing emails data
-func (c Client[M]) GetMailsPage(identifier string, page int) (*goquery.Document, error) {
-	URL, err := c.decorateURL("inbox?d=&ctrl=&scrl=&spam=true&ad=0&r_c=&id=", c.apiVersion, false, map[string]string{"login": identifier, "p": strconv.Itoa(page)})
-	if err != nil {
-		return nil, err
+// InboxURL returns the URL of an inbox in the web interface
+func InboxURL(baseURL string, name string) string {
+	return normalizeBaseURL(baseURL) + "/en/?login=" + url.QueryEscape(name)
+}
+
+func normalizeBaseURL(baseURL string) string {
+	if baseURL == "" {
+		return refURL
 	}
-	c.browser.populateCookieFromAccount(identifier)
-	content, err := c.browser.fetch("GET", URL, map[string]string{}, nil)
+	return strings.TrimSuffix(baseURL, "/")
+}
+
+// Convert returns a client fetching another kind of mail document
+// which shares the browser and the session of the given client
+func Convert[N MailDoc, M MailDoc](c Client[M]) Client[N] {
+	return Client[N]{browser: c.browser, session: c.session, baseURL: c.baseURL, sessionFile: c.sessionFile, location: c.location}
+}
+
+// GetMailsPage fetches all html pages containing emails data
+func (c Client[M]) GetMailsPage(ctx context.Context, identifier string, page int) (*goquery.Document, error) {
+	content, err := c.fetchInbox(ctx, identifier, "inbox?d=&ctrl=&scrl=&spam=true&ad=0&r_c=&id=", map[string]string{"login": identifier, "p": strconv.Itoa(page)})
 	if err != nil {
 		return nil, err
 	}
-	err = checkInboxCAPTCHA(content.String())
+	doc, err := goquery.NewDocumentFromReader(content)
 	if err != nil {
-		return nil, err
+		return nil, &ParseError{Page: "inbox", Err: err}
 	}
-	return goquery.NewDocumentFromReader(content)
+	return doc, nil
 }
 
 // GetMailPage fetches html page containing the email
-func (c Client[M]) GetMailPage(identifier string, mailID string) (doc M, err error) {
+func (c Client[M]) GetMailPage(ctx context.Context, identifier string, mailID string) (doc M, err error) {
 	var kind mailKind
 	switch any(doc).(type) {
 	case MailHTMLDoc:
 		kind = mailHTML
 	case MailSourceDoc:
 		kind = mailSource
+	case MailTextDoc:
+		kind = mailText
 	}
-	URL, err := c.decorateURL("mail", c.apiVersion, true, map[string]string{"b": identifier, "id": fmt.Sprintf("%s%s", kind, mailID)})
+	URL, err := c.decorateURL("mail", true, map[string]string{"b": identifier, "id": fmt.Sprintf("%s%s", kind, mailID)})
 	if err != nil {
 		return
 	}
-	c.browser.populateCookieFromAccount(identifier)
-	content, err := c.browser.fetch("GET", URL, map[string]string{}, nil)
-	if err != nil {
+	if err = c.populateCookieFromAccount(identifier); err != nil {
 		return
 	}
-	err = checkMailCAPTCHA(content.String())
-	if err != nil {
+	var content *bytes.Buffer
+	for {
+		content, err = c.browser.fetch(ctx, "GET", URL, map[string]string{}, nil)
+		if err != nil {
+			return
+		}
+		err = checkMailCAPTCHA(content.String())
+		if err == nil {
+			break
+		}
+		c.browser.throttle()
+		if err = c.waitCAPTCHA(ctx, identifier); err != nil {
+			return
+		}
+		if err = c.populateCookieFromAccount(identifier); err != nil {
+			return
+		}
+	}
+	if err = c.saveSession(); err != nil {
 		return
 	}
 	d, err := goquery.NewDocumentFromReader(content)
 	if err != nil {
+		err = &ParseError{Page: "mail", URL: URL, Err: err}
 		return
 	}
 	return M(*d), nil
 }
 
 // DeleteMail removes an email from yopmail inbox
-func (c Client[M]) DeleteMail(identifier string, mailID string) error {
-	URL, err := c.decorateURL("inbox?p=1&ctrl=&ad=0&r_c=&id=", c.apiVersion, false, map[string]string{"login": identifier, "d": mailID})
+func (c Client[M]) DeleteMail(ctx context.Context, identifier string, mailID string) error {
+	_, err := c.fetchInbox(ctx, identifier, "inbox?p=1&ctrl=&ad=0&r_c=&id=", map[string]string{"login": identifier, "d": mailID})
+	return err
+}
+
+// FlushMail removes all yopmail inbox mails
+func (c Client[M]) FlushMail(ctx context.Context, identifier string, mailID string) error {
+	_, err := c.fetchInbox(ctx, identifier, "inbox?p=1&d=all&ad=0&r_c=&id=", map[string]string{"login": identifier, "ctrl": mailID})
+	return err
+}
+
+// SendMail sends a mail from an inbox to another yopmail address,
+// from is the address of the inbox on any of the alias domains
+func (c Client[M]) SendMail(ctx context.Context, identifier string, from string, to string, subject string, body string) error {
+	form := url.Values{
+		"msgfrom":    {from},
+		"msgto":      {to},
+		"msgsubject": {subject},
+		"msgbody":    {body},
+	}
+	content, URL, err := c.fetchWithSession(ctx, identifier, "POST", "writepost", map[string]string{}, form, checkMailCAPTCHA)
 	if err != nil {
 		return err
 	}
-	c.browser.populateCookieFromAccount(identifier)
-	content, err := c.browser.fetch("GET", URL, map[string]string{}, nil)
+	// the errors are printed, the session tokens are kept out of them
+	URL = redactURLString(URL)
+	doc, err := goquery.NewDocumentFromReader(content)
 	if err != nil {
-		return err
+		return &ParseError{Page: "send confirmation", URL: URL, Err: err}
+	}
+	// yopmail displays a confirmation once the mail is accepted
+	if doc.Find("#msgsent").Length() == 0 {
+		return &SendError{URL: URL}
 	}
-	return checkInboxCAPTCHA(content.String())
+	return nil
 }
 
-// FlushMail removes all yopmail inbox mails
-func (c Client[M]) FlushMail(identifier string, mailID string) error {
-	URL, err := c.decorateURL("inbox?p=1&d=all&ad=0&r_c=&id=", c.apiVersion, false, map[string]string{"login": identifier, "ctrl": mailID})
+// fetchInbox queries the inbox endpoint
+func (c Client[M]) fetchInbox(ctx context.Context, identifier string, URL string, queryParams map[string]string) (*bytes.Buffer, error) {
+	content, _, err := c.fetchWithSession(ctx, identifier, "GET", URL, queryParams, nil, checkInboxCAPTCHA)
+	return content, err
+}
+
+// fetchWithSession queries an endpoint requiring the session tokens,
+// form is posted when not nil, if yopmail rejects the request while
+// the session tokens were reused, they may have expired so the session
+// is bootstrapped again and the request is replayed once, a CAPTCHA
+// required with fresh tokens is waited for when the client is
+// configured to, the URL of the last request is returned
+func (c Client[M]) fetchWithSession(ctx context.Context, identifier string, method string, URL string, queryParams map[string]string, form url.Values, checkCAPTCHA func(string) error) (*bytes.Buffer, string, error) {
+	for {
+		reused := !c.session.fresh
+		u, err := c.decorateURL(URL, false, queryParams)
+		if err != nil {
+			return nil, "", err
+		}
+		c.session.fresh = false
+		if err := c.populateCookieFromAccount(identifier); err != nil {
+			return nil, "", err
+		}
+		headers := map[string]string{}
+		var body io.Reader
+		if form != nil {
+			headers["Content-Type"] = "application/x-www-form-urlencoded"
+			body = strings.NewReader(form.Encode())
+		}
+		content, err := c.browser.fetch(ctx, method, u, headers, body)
+		if err != nil {
+			return nil, "", err
+		}
+		err = checkCAPTCHA(content.String())
+		if errors.Is(err, ErrCaptcha) {
+			c.browser.throttle()
+		}
+		if errors.Is(err, ErrCaptcha) && reused {
+			if err := c.bootstrap(ctx); err != nil {
+				return nil, "", err
+			}
+			continue
+		}
+		if errors.Is(err, ErrCaptcha) {
+			if err := c.waitCAPTCHA(ctx, identifier); err != nil {
+				return nil, "", err
+			}
+			continue
+		}
+		return content, u, c.saveSession()
+	}
+}
+
+// bootstrap scrapes the api version and the yp and yj tokens
+// from yopmail and stores them in the session
+func (c Client[M]) bootstrap(ctx context.Context) error {
+	content, err := c.browser.fetch(ctx, "GET", c.baseURL, map[string]string{}, nil)
 	if err != nil {
 		return err
 	}
-	c.browser.populateCookieFromAccount(identifier)
-	content, err := c.browser.fetch("GET", URL, map[string]string{}, nil)
+	apiVersion, err := parseApiVersion(content.String())
 	if err != nil {
-		return err
+		return &TokenError{Token: "api version", URL: c.baseURL}
 	}
-	return checkInboxCAPTCHA(content.String())
-}
-
-func (c Client[M]) decorateURL(URL string, apiVersion string, disableDefaultQueryParams bool, queryParams map[string]string) (string, error) {
-	doc, err := c.browser.fetchDocument("GET", refURL, map[string]string{}, nil)
+	doc, err := goquery.NewDocumentFromReader(content)
 	if err != nil {
-		return "", err
+		return &ParseError{Page: "home page", URL: c.baseURL, Err: err}
 	}
-
 	var yp string
 	var ok bool
 	doc.Find("#yp").Each(func(i int, s *goquery.Selection) {
 		yp, ok = s.Attr("value")
 	})
 	if !ok || yp == "" {
-		return "", errors.New("failure when fetching yp value")
+		return &TokenError{Token: "yp", URL: c.baseURL}
 	}
-	doc, err = c.browser.fetchDocument("GET", refURL+"/ver/"+apiVersion+"/webmail.js", map[string]string{}, nil)
+	webmailURL := c.baseURL + "/ver/" + apiVersion + "/webmail.js"
+	doc, err = c.browser.fetchDocument(ctx, "GET", webmailURL, map[string]string{}, nil)
 	if err != nil {
-		return "", err
+		return err
 	}
 	m := regexp.MustCompile("&yj=(.*?)&").FindStringSubmatch(doc.Text())
 	if len(m) != 2 {
-		return "", errors.New("failure when fetching yj value")
+		return &TokenError{Token: "yj", URL: webmailURL}
+	}
+	*c.session = session{apiVersion: apiVersion, yp: yp, yj: m[1], fresh: true}
+	return c.saveSession()
+}
+
+// saveSession persists the session and the cookies
+// when a session file is configured
+func (c Client[M]) saveSession() error {
+	if c.sessionFile == "" {
+		return nil
+	}
+	err := saveSession(c.sessionFile, c.baseURL, SessionState{
+		APIVersion: c.session.apiVersion,
+		YP:         c.session.yp,
+		YJ:         c.session.yj,
+		Cookies:    c.browser.jar.all(),
+		UpdatedAt:  time.Now(),
+	})
+	if err != nil {
+		return wrapError("failure when saving session", err)
 	}
-	yj := m[1]
-	u, err := url.Parse(refURL + "/" + URL)
+	return nil
+}
+
+// populateCookieFromAccount sets the cookies the yopmail
+// web interface sets from javascript when an inbox is opened
+func (c Client[M]) populateCookieFromAccount(account string) error {
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return err
+	}
+	c.browser.jar.SetCookies(u, []*http.Cookie{
+		{Name: "compte", Value: account},
+		{Name: "ywm", Value: account},
+		{Name: "ytime", Value: time.Now().Format("15:04")},
+	})
+	return nil
+}
+
+func (c Client[M]) decorateURL(URL string, disableDefaultQueryParams bool, queryParams map[string]string) (string, error) {
+	u, err := url.Parse(c.baseURL + "/" + URL)
 	if err != nil {
 		return "", err
 	}
 	q := u.Query()
 	if !disableDefaultQueryParams {
-		q.Add("yp", yp)
-		q.Add("yj", yj)
-		q.Add("v", apiVersion)
+		q.Add("yp", c.session.yp)
+		q.Add("yj", c.session.yj)
+		q.Add("v", c.session.apiVersion)
 	}
 	for k, v := range queryParams {
 		q.Add(k, v)
@@ -182,113 +372,167 @@ func (c Client[M]) decorateURL(URL string, apiVersion string, disableDefaultQuer
 }
 
 type browser struct {
-	cookies           map[string]string
-	enableDebugMode   bool
+	jar               *jar
+	logger            *slog.Logger
+	har               *HARRecorder
+	retryPolicy       RetryPolicy
+	limiter           *limiter
+	captcha           *captchaWaiter
 	httpClientFactory httpClientFactory
 }
 
-func newBrowser(enableDebugMode bool) *browser {
+func newBrowser(config Config) *browser {
 	return &browser{
-		cookies:           map[string]string{},
-		enableDebugMode:   enableDebugMode,
+		jar:               newJar(),
+		logger:            newLogger(config),
+		har:               config.HAR,
+		retryPolicy:       config.Retry,
+		limiter:           newLimiter(config.RateLimit),
+		captcha:           newCaptchaWaiter(config.Captcha),
 		httpClientFactory: httpClientFactory{},
 	}
 }
 
-func (b *browser) setCookie(key string, value string) {
-	b.cookies[key] = value
-}
-
-func (b *browser) populateCookieFromAccount(account string) {
-	for k, v := range map[string]string{"compte": account, "ywm": account, "ytime": time.Now().Format("15:04")} {
-		b.setCookie(k, v)
+// throttle slows down the pace of the requests when
+// yopmail looks close to require a CAPTCHA
+func (b *browser) throttle() {
+	if b.limiter == nil {
+		return
 	}
+	b.limiter.slowDown()
+	b.logger.Warn("rate limit: slowing down the requests", "requestsPerMinute", b.limiter.requestsPerMinute())
 }
 
-func (b *browser) buildCookie() string {
-	data := []string{}
-	for k, v := range b.cookies {
-		data = append(data, fmt.Sprintf("%s=%s", k, v))
+func (b *browser) fetch(ctx context.Context, method string, URL string, headers map[string]string, body io.Reader) (*bytes.Buffer, error) {
+	// the errors are printed, the session tokens are kept out of them
+	redactedURL := redactURLString(URL)
+	errMsg := fmt.Sprintf("failure when fetching %s", redactedURL)
+	maxAttempts := 1
+	if isIdempotent(method) && b.retryPolicy.MaxAttempts > 1 {
+		maxAttempts = b.retryPolicy.MaxAttempts
+	}
+	for attempt := 1; ; attempt++ {
+		if err := b.limiter.wait(ctx); err != nil {
+			return nil, wrapError(errMsg, err)
+		}
+		buf, err := b.fetchOnce(ctx, method, URL, headers, body)
+		if err == nil {
+			return buf, nil
+		}
+		if attempt > 1 {
+			errMsg = fmt.Sprintf("failure when fetching %s after %d attempts", redactedURL, attempt)
+		}
+		var retryErr *retryableError
+		if !errors.As(err, &retryErr) {
+			return nil, wrapError(errMsg, err)
+		}
+		if attempt >= maxAttempts || ctx.Err() != nil {
+			return nil, wrapError(errMsg, retryErr.err)
+		}
+		delay := b.retryPolicy.backoff(attempt, retryErr.retryAfter)
+		b.logger.Warn("retrying request", "method", method, "url", redactedURL, "attempt", attempt, "maxAttempts", maxAttempts, "delay", delay, "error", retryErr.err)
+		select {
+		case <-ctx.Done():
+			return nil, wrapError(errMsg, ctx.Err())
+		case <-time.After(delay):
+		}
 	}
-	return strings.Join(data, "; ")
 }
 
-func (b *browser) fetch(method string, URL string, headers map[string]string, body io.Reader) (*bytes.Buffer, error) {
+func (b *browser) fetchOnce(ctx context.Context, method string, URL string, headers map[string]string, body io.Reader) (*bytes.Buffer, error) {
 	ID := uuid.New()
-	errMsg := fmt.Sprintf("failure when fetching %s", URL)
-	r, err := http.NewRequest(method, URL, body)
+	debug := b.logger.Enabled(ctx, slog.LevelDebug)
+	var requestBody []byte
+	if (debug || b.har != nil) && body != nil {
+		var err error
+		requestBody, err = io.ReadAll(body)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(requestBody)
+	}
+	r, err := http.NewRequestWithContext(ctx, method, URL, body)
 	if err != nil {
-		return nil, wrapError(errMsg, err)
+		return nil, err
 	}
 
 	for k, v := range headers {
 		r.Header.Add(k, v)
 	}
-	if len(b.cookies) > 0 {
-		r.Header.Add("Cookie", b.buildCookie())
+	for _, c := range b.jar.Cookies(r.URL) {
+		r.AddCookie(c)
 	}
 	userAgent := os.Getenv("YOGO_USER_AGENT")
 	if userAgent == "" {
 		userAgent = defaultUserAgent
 	}
 	r.Header.Add("User-Agent", userAgent)
-	if b.enableDebugMode {
-		buff, err := httputil.DumpRequest(r, true)
-		if err != nil {
-			return nil, err
-		}
-		fmt.Printf("\n---- REQUEST %s ----\n", ID)
-		fmt.Println(string(buff))
-		fmt.Println("------------------------------------------------------")
+	if debug {
+		b.logger.DebugContext(ctx, "request", "id", ID, "method", method, "url", redactURL(r.URL), "headers", redactHeaders(r.Header), "body", redactBody(requestBody))
 	}
 
 	c, err := b.httpClientFactory.create()
 	if err != nil {
-		return nil, wrapError(errMsg, err)
+		return nil, err
 	}
+	start := time.Now()
 	res, err := c.Do(r)
 	if err != nil {
-		return nil, wrapError(errMsg, err)
-	}
-	if b.enableDebugMode {
-		buff, err := httputil.DumpResponse(res, true)
-		if err != nil {
-			return nil, err
+		var URLErr *url.Error
+		if errors.As(err, &URLErr) {
+			URLErr.URL = redactURLString(URLErr.URL)
+		}
+		now := time.Now()
+		b.har.record(r, requestBody, nil, nil, start, now, now, err)
+		b.logger.DebugContext(ctx, "request failed", "id", ID, "duration", time.Since(start), "error", err)
+		if ctx.Err() != nil {
+			return nil, &NetworkError{URL: URL, Err: err}
 		}
-		fmt.Printf("\n---- RESPONSE %s ----\n", ID)
-		fmt.Println(string(buff))
-		fmt.Println("-------------------------------------------------------")
+		return nil, &retryableError{err: &NetworkError{URL: URL, Err: err}}
+	}
+	defer res.Body.Close()
+	headersReceived := time.Now()
+	content, err := io.ReadAll(res.Body)
+	b.har.record(r, requestBody, res, content, start, headersReceived, time.Now(), err)
+	if debug {
+		b.logger.DebugContext(ctx, "response", "id", ID, "status", res.StatusCode, "duration", time.Since(start), "headers", redactHeaders(res.Header), "body", redactBody(content))
 	}
 	if res.StatusCode > 300 {
-		b, err := io.ReadAll(res.Body)
 		if err != nil {
-			return nil, wrapError(errMsg, errors.New("can't extract request body"))
+			return nil, &NetworkError{URL: URL, Err: errors.New("can't extract request body")}
 		}
 
-		return nil, wrapError(errMsg, fmt.Errorf("request failed with error code %d and body %s", res.StatusCode, string(b)))
+		err := &HTTPError{URL: URL, StatusCode: res.StatusCode, Body: string(content)}
+		if isRetryableStatus(res.StatusCode) {
+			b.throttle()
+			return nil, &retryableError{err: err, retryAfter: parseRetryAfter(res.Header.Get("Retry-After"), time.Now())}
+		}
+		return nil, err
 	}
-	buf, err := io.ReadAll(res.Body)
 	if err != nil {
-		return nil, wrapError(errMsg, err)
+		return nil, &retryableError{err: &NetworkError{URL: URL, Err: err}}
 	}
-	for _, c := range res.Cookies() {
-		b.cookies[c.Name] = c.Value
-	}
-	return bytes.NewBuffer(buf), nil
+	b.jar.SetCookies(r.URL, res.Cookies())
+	b.limiter.speedUp()
+	return bytes.NewBuffer(content), nil
 }
 
-func (b *browser) fetchDocument(method string, URL string, headers map[string]string, body io.Reader) (*goquery.Document, error) {
-	r, err := b.fetch("GET", URL, headers, body)
+func (b *browser) fetchDocument(ctx context.Context, method string, URL string, headers map[string]string, body io.Reader) (*goquery.Document, error) {
+	r, err := b.fetch(ctx, "GET", URL, headers, body)
 	if err != nil {
 		return nil, err
 	}
-	return goquery.NewDocumentFromReader(r)
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, &ParseError{Page: "document", URL: URL, Err: err}
+	}
+	return doc, nil
 }
 
 func parseApiVersion(s string) (string, error) {
 	data := regexp.MustCompile(`<script src="/ver/([0-9.]+)/webmail.js">`).FindStringSubmatch(s)
 	if len(data) < 2 {
-		return "", errors.New("api version could not be extracted")
+		return "", &TokenError{Token: "api version"}
 	}
 
 	return data[1], nil
@@ -1,10 +1,12 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -29,7 +31,9 @@ func TestParseApiVersion(t *testing.T) {
 			return ""
 		}, func(version string, err error) {
 			assert.Error(t, err)
-			assert.EqualError(t, err, "api version could not be extracted")
+			assert.EqualError(t, err, "failure when fetching api version value")
+			var tokenErr *TokenError
+			assert.ErrorAs(t, err, &tokenErr)
 		},
 	}, {
 		"version found in JS file",
@@ -80,10 +84,10 @@ func TestFetchDocument(t *testing.T) {
 			},
 		}} {
 		t.Run(s.name, func(t *testing.T) {
-			b := newBrowser(false)
+			b := newBrowser(Config{})
 
 			s.setup()
-			s.test(b.fetchDocument("GET", "http://abcdefg.com", map[string]string{}, nil))
+			s.test(b.fetchDocument(context.Background(), "GET", "http://abcdefg.com", map[string]string{}, nil))
 			httpmock.Reset()
 		})
 	}
@@ -107,6 +111,9 @@ func TestFetch(t *testing.T) {
 		}, func(reader io.Reader, err error) {
 			assert.Error(t, err)
 			assert.EqualError(t, err, `failure when fetching http://hijklm.com : request failed with error code 500 and body `)
+			var HTTPErr *HTTPError
+			assert.ErrorAs(t, err, &HTTPErr)
+			assert.Equal(t, &HTTPError{URL: "http://hijklm.com", StatusCode: 500}, HTTPErr)
 		},
 	}, {
 		"error when fetching the request",
@@ -116,6 +123,9 @@ func TestFetch(t *testing.T) {
 		}, func(reader io.Reader, err error) {
 			assert.Error(t, err)
 			assert.EqualError(t, err, `failure when fetching http://hijklm.com : Get "http://hijklm.com": an error occurred`)
+			var networkErr *NetworkError
+			assert.ErrorAs(t, err, &networkErr)
+			assert.Equal(t, "http://hijklm.com", networkErr.URL)
 		},
 	}, {
 		"request timeout",
@@ -142,20 +152,48 @@ func TestFetch(t *testing.T) {
 		},
 	}} {
 		t.Run(s.name, func(t *testing.T) {
-			b := newBrowser(false)
+			b := newBrowser(Config{})
 			s.setup()
-			s.test(b.fetch("GET", "http://hijklm.com", map[string]string{"header1": "value1", "header2": "value2"}, nil))
+			s.test(b.fetch(context.Background(), "GET", "http://hijklm.com", map[string]string{"header1": "value1", "header2": "value2"}, nil))
 			httpmock.Reset()
 		})
 	}
 }
 
-func TestDecorateURL(t *testing.T) {
+func TestFetchErrorWithSessionTokens(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://hijklm.com/en/inbox",
+		httpmock.NewErrorResponder(errors.New("an error occurred")))
+
+	b := newBrowser(Config{})
+	_, err := b.fetch(context.Background(), "GET", "http://hijklm.com/en/inbox?login=test&yj=yjtest&yp=yptest", map[string]string{}, nil)
+	assert.EqualError(t, err, `failure when fetching http://hijklm.com/en/inbox?login=test&yj=REDACTED&yp=REDACTED : Get "http://hijklm.com/en/inbox?login=test&yj=REDACTED&yp=REDACTED": an error occurred`)
+}
+
+func TestFetchWithCancelledContext(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://hijklm.com",
+		func(r *http.Request) (*http.Response, error) {
+			<-r.Context().Done()
+			return nil, r.Context().Err()
+		})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	b := newBrowser(Config{})
+	_, err := b.fetch(ctx, "GET", "http://hijklm.com", map[string]string{}, nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestBootstrap(t *testing.T) {
 	type scenario struct {
 		name  string
 		setup func()
-		args  func() (string, string, bool, map[string]string)
-		test  func(string, error)
+		test  func(*session, error)
 	}
 
 	httpmock.Activate()
@@ -166,34 +204,45 @@ func TestDecorateURL(t *testing.T) {
 		func() {
 			httpmock.RegisterResponder("GET", refURL,
 				httpmock.NewStringResponder(500, ""))
-		}, func() (string, string, bool, map[string]string) {
-			return "test", "3.1", false, map[string]string{"q1": "value1", "q2": "value2"}
 		},
-		func(URL string, err error) {
+		func(session *session, err error) {
 			assert.Error(t, err)
 			assert.EqualError(t, err, `failure when fetching https://yopmail.com : request failed with error code 500 and body `)
 		},
+	}, {
+		"no api version found",
+		func() {
+			httpmock.RegisterResponder("GET", refURL,
+				httpmock.NewStringResponder(200, `<html><head></head><body><input id="yp" value="yptest"></body></html>`))
+		}, func(session *session, err error) {
+			assert.Error(t, err)
+			assert.EqualError(t, err, "failure when fetching api version value")
+			var tokenErr *TokenError
+			assert.ErrorAs(t, err, &tokenErr)
+		},
 	}, {
 		"no attribute yp found",
 		func() {
 			httpmock.RegisterResponder("GET", refURL,
-				httpmock.NewStringResponder(200, ""))
-		}, func() (string, string, bool, map[string]string) {
-			return "test", "3.1", false, map[string]string{"q1": "value1", "q2": "value2"}
-		}, func(URL string, err error) {
+				httpmock.NewStringResponder(200, `<script src="/ver/3.1/webmail.js"></script>`))
+		}, func(session *session, err error) {
 			assert.Error(t, err)
 			assert.EqualError(t, err, "failure when fetching yp value")
+			var tokenErr *TokenError
+			assert.ErrorAs(t, err, &tokenErr)
+			assert.Equal(t, "yp", tokenErr.Token)
 		},
 	}, {
 		"attribute yp with no value",
 		func() {
 			httpmock.RegisterResponder("GET", refURL,
-				httpmock.NewStringResponder(200, `<html><head></head><body><input id="yp"></body></html>`))
-		}, func() (string, string, bool, map[string]string) {
-			return "test", "3.1", false, map[string]string{"q1": "value1", "q2": "value2"}
-		}, func(URL string, err error) {
+				httpmock.NewStringResponder(200, `<script src="/ver/3.1/webmail.js"></script><input id="yp">`))
+		}, func(session *session, err error) {
 			assert.Error(t, err)
 			assert.EqualError(t, err, "failure when fetching yp value")
+			var tokenErr *TokenError
+			assert.ErrorAs(t, err, &tokenErr)
+			assert.Equal(t, "yp", tokenErr.Token)
 		},
 	}, {
 		"failure when fetching the JS file",
@@ -201,10 +250,8 @@ func TestDecorateURL(t *testing.T) {
 			httpmock.RegisterResponder("GET", refURL+"/ver/3.1/webmail.js",
 				httpmock.NewStringResponder(500, ""))
 			httpmock.RegisterResponder("GET", refURL,
-				httpmock.NewStringResponder(200, `<html><head></head><body><input id="yp" value="yptest"></body></html>`))
-		}, func() (string, string, bool, map[string]string) {
-			return "test", "3.1", false, map[string]string{"q1": "value1", "q2": "value2"}
-		}, func(URL string, err error) {
+				httpmock.NewStringResponder(200, `<script src="/ver/3.1/webmail.js"></script><input id="yp" value="yptest">`))
+		}, func(session *session, err error) {
 			assert.Error(t, err)
 			assert.EqualError(t, err, "failure when fetching https://yopmail.com/ver/3.1/webmail.js : request failed with error code 500 and body ")
 		},
@@ -214,48 +261,62 @@ func TestDecorateURL(t *testing.T) {
 			httpmock.RegisterResponder("GET", refURL+"/ver/3.1/webmail.js",
 				httpmock.NewStringResponder(200, ""))
 			httpmock.RegisterResponder("GET", refURL,
-				httpmock.NewStringResponder(200, `<html><head></head><body><input id="yp" value="yptest"></body></html>`))
-		}, func() (string, string, bool, map[string]string) {
-			return "test", "3.1", false, map[string]string{"q1": "value1", "q2": "value2"}
-		}, func(URL string, err error) {
+				httpmock.NewStringResponder(200, `<script src="/ver/3.1/webmail.js"></script><input id="yp" value="yptest">`))
+		}, func(session *session, err error) {
 			assert.Error(t, err)
 			assert.EqualError(t, err, "failure when fetching yj value")
+			var tokenErr *TokenError
+			assert.ErrorAs(t, err, &tokenErr)
+			assert.Equal(t, "yj", tokenErr.Token)
 		},
 	}, {
-		"failure when parsing the URL",
+		"populate the session",
 		func() {
-			httpmock.RegisterResponder("GET", refURL+"/ver/3.1/webmail.js",
-				httpmock.NewStringResponder(200, "xxx http://whatever.com?q=s&yj=ytest&t=a xxxxx"))
-			httpmock.RegisterResponder("GET", refURL,
-				httpmock.NewStringResponder(200, `<html><head></head><body><input id="yp" value="yptest"</body></html>`))
-		}, func() (string, string, bool, map[string]string) {
-			return "\n\n", "3.1", false, map[string]string{"q1": "value1", "q2": "value2"}
+			mockYopmailSetup()
+		}, func(s *session, err error) {
+			assert.NoError(t, err)
+			assert.Equal(t, &session{apiVersion: "3.1", yp: "yptest", yj: "ytest", fresh: true}, s)
+		},
+	}} {
+		t.Run(s.name, func(t *testing.T) {
+			s.setup()
+			c := Client[MailHTMLDoc]{browser: newBrowser(Config{}), session: &session{}, baseURL: refURL}
+			s.test(c.session, c.bootstrap(context.Background()))
+			httpmock.Reset()
+		})
+	}
+}
+
+func TestDecorateURL(t *testing.T) {
+	type scenario struct {
+		name string
+		args func() (string, bool, map[string]string)
+		test func(string, error)
+	}
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	for _, s := range []scenario{{
+		"failure when parsing the URL",
+		func() (string, bool, map[string]string) {
+			return "\n\n", false, map[string]string{"q1": "value1", "q2": "value2"}
 		}, func(URL string, err error) {
 			assert.Error(t, err)
 			assert.EqualError(t, err, `parse "https://yopmail.com/\n\n": net/url: invalid control character in URL`)
 		},
 	}, {
 		"decorate the URL",
-		func() {
-			httpmock.RegisterResponder("GET", refURL+"/ver/3.1/webmail.js",
-				httpmock.NewStringResponder(200, "xxx http://whatever.com?q=s&yj=ytest&t=a xxxxx"))
-			httpmock.RegisterResponder("GET", refURL,
-				httpmock.NewStringResponder(200, `<html><head></head><body><input id="yp" value="yptest"></body></html>`))
-		}, func() (string, string, bool, map[string]string) {
-			return "test?k=w&g=t", "3.1", false, map[string]string{"q1": "value1", "q2": "value2"}
+		func() (string, bool, map[string]string) {
+			return "test?k=w&g=t", false, map[string]string{"q1": "value1", "q2": "value2"}
 		}, func(URL string, err error) {
 			assert.NoError(t, err)
 			assert.Equal(t, refURL+"/en/test?g=t&k=w&q1=value1&q2=value2&v=3.1&yj=ytest&yp=yptest", URL)
 		},
 	}, {
 		"decorate the URL and do not add default query params",
-		func() {
-			httpmock.RegisterResponder("GET", refURL+"/ver/3.1/webmail.js",
-				httpmock.NewStringResponder(200, "xxx http://whatever.com?q=s&yj=ytest&t=a xxxxx"))
-			httpmock.RegisterResponder("GET", refURL,
-				httpmock.NewStringResponder(200, `<html><head></head><body><input id="yp" value="yptest"></body></html>`))
-		}, func() (string, string, bool, map[string]string) {
-			return "test?k=w&g=t", "3.1", true, map[string]string{"q1": "value1", "q2": "value2"}
+		func() (string, bool, map[string]string) {
+			return "test?k=w&g=t", true, map[string]string{"q1": "value1", "q2": "value2"}
 		}, func(URL string, err error) {
 			assert.NoError(t, err)
 			assert.Equal(t, refURL+"/en/test?g=t&k=w&q1=value1&q2=value2", URL)
@@ -264,11 +325,11 @@ func TestDecorateURL(t *testing.T) {
 		t.Run(s.name, func(t *testing.T) {
 			mockYopmailSetup()
 
-			c, err := New[MailHTMLDoc](false)
+			c, err := New[MailHTMLDoc](context.Background(), Config{})
 			assert.NoError(t, err)
 
-			s.setup()
 			s.test(c.decorateURL(s.args()))
+			assert.Equal(t, 1, httpmock.GetCallCountInfo()["GET "+refURL])
 			httpmock.Reset()
 		})
 	}
@@ -295,7 +356,7 @@ func TestGetMailsPage(t *testing.T) {
 		},
 		func(doc *goquery.Document, err error) {
 			assert.Error(t, err)
-			assert.EqualError(t, err, `failure when fetching https://yopmail.com/en/inbox?ad=0&ctrl=&d=&id=&login=box1&p=1&r_c=&scrl=&spam=true&v=3.1&yj=ytest&yp=yptest : request failed with error code 500 and body `)
+			assert.EqualError(t, err, `failure when fetching https://yopmail.com/en/inbox?ad=0&ctrl=&d=&id=&login=box1&p=1&r_c=&scrl=&spam=true&v=3.1&yj=REDACTED&yp=REDACTED : request failed with error code 500 and body `)
 		},
 	}, {
 		"CAPTCHA activated",
@@ -324,11 +385,90 @@ func TestGetMailsPage(t *testing.T) {
 		t.Run(s.name, func(t *testing.T) {
 			mockYopmailSetup()
 
-			c, err := New[MailHTMLDoc](false)
+			c, err := New[MailHTMLDoc](context.Background(), Config{})
+			assert.NoError(t, err)
+
+			s.setup()
+			identifier, page := s.args()
+			s.test(c.GetMailsPage(context.Background(), identifier, page))
+			httpmock.Reset()
+		})
+	}
+}
+
+func TestGetMailsPageReusesSession(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	mockYopmailSetup()
+	for _, page := range []string{"1", "2", "3"} {
+		httpmock.RegisterResponder("GET", refURL+"/en/inbox?ad=0&ctrl=&d=&id=&login=box1&p="+page+"&r_c=&scrl=&spam=true&v=3.1&yj=ytest&yp=yptest",
+			httpmock.NewStringResponder(200, "w.finrmail(25,2,1,0,0,'alt.zk-4nyqp5l','')"))
+	}
+
+	c, err := New[MailHTMLDoc](context.Background(), Config{})
+	assert.NoError(t, err)
+	for page := 1; page <= 3; page++ {
+		_, err := c.GetMailsPage(context.Background(), "box1", page)
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, 1, httpmock.GetCallCountInfo()["GET "+refURL])
+	assert.Equal(t, 1, httpmock.GetCallCountInfo()["GET "+refURL+"/ver/3.1/webmail.js"])
+}
+
+func TestGetMailsPageRefreshesStaleSession(t *testing.T) {
+	type scenario struct {
+		name  string
+		setup func()
+		test  func(error)
+	}
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	for _, s := range []scenario{{
+		"stale tokens are replaced",
+		func() {
+			httpmock.RegisterResponder("GET", refURL+"/en/inbox?ad=0&ctrl=&d=&id=&login=box1&p=2&r_c=&scrl=&spam=true&v=3.1&yj=ytest&yp=yptest",
+				httpmock.NewStringResponder(200, ""))
+			httpmock.RegisterResponder("GET", refURL+"/en/inbox?ad=0&ctrl=&d=&id=&login=box1&p=2&r_c=&scrl=&spam=true&v=3.2&yj=ytest2&yp=yptest2",
+				httpmock.NewStringResponder(200, "w.finrmail(25,2,1,0,0,'alt.zk-4nyqp5l','')"))
+		},
+		func(err error) {
+			assert.NoError(t, err)
+			assert.Equal(t, 1, httpmock.GetCallCountInfo()["GET "+refURL+"/ver/3.2/webmail.js"])
+		},
+	}, {
+		"CAPTCHA activated after a refresh",
+		func() {
+			httpmock.RegisterResponder("GET", refURL+"/en/inbox?ad=0&ctrl=&d=&id=&login=box1&p=2&r_c=&scrl=&spam=true&v=3.1&yj=ytest&yp=yptest",
+				httpmock.NewStringResponder(200, ""))
+			httpmock.RegisterResponder("GET", refURL+"/en/inbox?ad=0&ctrl=&d=&id=&login=box1&p=2&r_c=&scrl=&spam=true&v=3.2&yj=ytest2&yp=yptest2",
+				httpmock.NewStringResponder(200, ""))
+		},
+		func(err error) {
+			assert.ErrorIs(t, err, ErrCaptcha)
+			assert.Equal(t, 1, httpmock.GetCallCountInfo()["GET "+refURL+"/ver/3.2/webmail.js"])
+		},
+	}} {
+		t.Run(s.name, func(t *testing.T) {
+			mockYopmailSetup()
+			httpmock.RegisterResponder("GET", refURL+"/en/inbox?ad=0&ctrl=&d=&id=&login=box1&p=1&r_c=&scrl=&spam=true&v=3.1&yj=ytest&yp=yptest",
+				httpmock.NewStringResponder(200, "w.finrmail(25,2,1,0,0,'alt.zk-4nyqp5l','')"))
+
+			c, err := New[MailHTMLDoc](context.Background(), Config{})
+			assert.NoError(t, err)
+			_, err = c.GetMailsPage(context.Background(), "box1", 1)
 			assert.NoError(t, err)
 
+			httpmock.RegisterResponder("GET", refURL+"/ver/3.2/webmail.js",
+				httpmock.NewStringResponder(200, "xxx http://whatever.com?q=s&yj=ytest2&t=a xxxxx"))
+			httpmock.RegisterResponder("GET", refURL,
+				httpmock.NewStringResponder(200, `<script src="/ver/3.2/webmail.js"></script><input id="yp" value="yptest2">`))
 			s.setup()
-			s.test(c.GetMailsPage(s.args()))
+			_, err = c.GetMailsPage(context.Background(), "box1", 2)
+			s.test(err)
 			httpmock.Reset()
 		})
 	}
@@ -384,16 +524,46 @@ func TestGetMailPage(t *testing.T) {
 		t.Run(s.name, func(t *testing.T) {
 			mockYopmailSetup()
 
-			c, err := New[MailHTMLDoc](false)
+			c, err := New[MailHTMLDoc](context.Background(), Config{})
 			assert.NoError(t, err)
 
 			s.setup()
-			s.test(c.GetMailPage(s.args()))
+			identifier, mailID := s.args()
+			s.test(c.GetMailPage(context.Background(), identifier, mailID))
 			httpmock.Reset()
 		})
 	}
 }
 
+func TestGetMailPageKind(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	mockYopmailSetup()
+
+	for ID, get := range map[string]func(Client[MailHTMLDoc]) error{
+		"mABCDEFGH": func(c Client[MailHTMLDoc]) error {
+			_, err := c.GetMailPage(context.Background(), "box1", "ABCDEFGH")
+			return err
+		},
+		"tABCDEFGH": func(c Client[MailHTMLDoc]) error {
+			_, err := Convert[MailTextDoc](c).GetMailPage(context.Background(), "box1", "ABCDEFGH")
+			return err
+		},
+		"sABCDEFGH": func(c Client[MailHTMLDoc]) error {
+			_, err := Convert[MailSourceDoc](c).GetMailPage(context.Background(), "box1", "ABCDEFGH")
+			return err
+		},
+	} {
+		t.Run(ID, func(t *testing.T) {
+			c, err := New[MailHTMLDoc](context.Background(), Config{})
+			assert.NoError(t, err)
+			httpmock.RegisterResponder("GET", refURL+"/en/mail?b=box1&id="+ID, httpmock.NewStringResponder(200, "<div id=\"mail\"></div>"))
+			assert.NoError(t, get(c))
+			assert.Equal(t, 1, httpmock.GetCallCountInfo()["GET "+refURL+"/en/mail?b=box1&id="+ID])
+		})
+	}
+}
+
 func TestDeleteMail(t *testing.T) {
 	type scenario struct {
 		name  string
@@ -415,7 +585,7 @@ func TestDeleteMail(t *testing.T) {
 		},
 		func(err error) {
 			assert.Error(t, err)
-			assert.EqualError(t, err, `failure when fetching https://yopmail.com/en/inbox?ad=0&ctrl=&d=ABCDEFGH&id=&login=box1&p=1&r_c=&v=3.1&yj=ytest&yp=yptest : Get "https://yopmail.com/en/inbox?ad=0&ctrl=&d=ABCDEFGH&id=&login=box1&p=1&r_c=&v=3.1&yj=ytest&yp=yptest": no responder found`)
+			assert.EqualError(t, err, `failure when fetching https://yopmail.com/en/inbox?ad=0&ctrl=&d=ABCDEFGH&id=&login=box1&p=1&r_c=&v=3.1&yj=REDACTED&yp=REDACTED : Get "https://yopmail.com/en/inbox?ad=0&ctrl=&d=ABCDEFGH&id=&login=box1&p=1&r_c=&v=3.1&yj=REDACTED&yp=REDACTED": no responder found`)
 		},
 	}, {
 		"CAPTCHA activated",
@@ -444,11 +614,12 @@ func TestDeleteMail(t *testing.T) {
 		t.Run(s.name, func(t *testing.T) {
 			mockYopmailSetup()
 
-			c, err := New[MailHTMLDoc](false)
+			c, err := New[MailHTMLDoc](context.Background(), Config{})
 			assert.NoError(t, err)
 
 			s.setup()
-			s.test(c.DeleteMail(s.args()))
+			identifier, mailID := s.args()
+			s.test(c.DeleteMail(context.Background(), identifier, mailID))
 			httpmock.Reset()
 		})
 	}
@@ -475,7 +646,7 @@ func TestFlushMail(t *testing.T) {
 		},
 		func(err error) {
 			assert.Error(t, err)
-			assert.EqualError(t, err, `failure when fetching https://yopmail.com/en/inbox?ad=0&ctrl=ABCDEFGH&d=all&id=&login=box1&p=1&r_c=&v=3.1&yj=ytest&yp=yptest : request failed with error code 500 and body `)
+			assert.EqualError(t, err, `failure when fetching https://yopmail.com/en/inbox?ad=0&ctrl=ABCDEFGH&d=all&id=&login=box1&p=1&r_c=&v=3.1&yj=REDACTED&yp=REDACTED : request failed with error code 500 and body `)
 		},
 	}, {
 		"CAPTCHA activated",
@@ -504,11 +675,82 @@ func TestFlushMail(t *testing.T) {
 		t.Run(s.name, func(t *testing.T) {
 			mockYopmailSetup()
 
-			c, err := New[MailHTMLDoc](false)
+			c, err := New[MailHTMLDoc](context.Background(), Config{})
 			assert.NoError(t, err)
 
 			s.setup()
-			s.test(c.FlushMail(s.args()))
+			identifier, mailID := s.args()
+			s.test(c.FlushMail(context.Background(), identifier, mailID))
+			httpmock.Reset()
+		})
+	}
+}
+
+func TestSendMail(t *testing.T) {
+	type scenario struct {
+		name  string
+		setup func()
+		test  func(error)
+	}
+
+	sendURL := refURL + "/en/writepost?v=3.1&yj=ytest&yp=yptest"
+	form := "msgbody=Hello+world&msgfrom=box1%40yopmail.fr&msgsubject=Hi&msgto=box2%40yopmail.com"
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	for _, s := range []scenario{{
+		"500 when requesting yopmail",
+		func() {
+			httpmock.RegisterResponder("POST", sendURL, httpmock.NewStringResponder(500, ""))
+		},
+		func(err error) {
+			assert.EqualError(t, err, `failure when fetching https://yopmail.com/en/writepost?v=3.1&yj=REDACTED&yp=REDACTED : request failed with error code 500 and body `)
+			assert.Equal(t, 1, httpmock.GetCallCountInfo()["POST "+sendURL])
+		},
+	}, {
+		"CAPTCHA activated",
+		func() {
+			httpmock.RegisterResponder("POST", sendURL, httpmock.NewStringResponder(200, "window.showRc()"))
+		},
+		func(err error) {
+			assert.ErrorIs(t, err, ErrCaptcha)
+		},
+	}, {
+		"request succeed",
+		func() {
+			httpmock.RegisterResponder("POST", sendURL,
+				func(r *http.Request) (*http.Response, error) {
+					b, err := io.ReadAll(r.Body)
+					assert.NoError(t, err)
+					assert.Equal(t, form, string(b))
+					assert.Equal(t, "application/x-www-form-urlencoded", r.Header.Get("Content-Type"))
+					return httpmock.NewStringResponse(200, `<html><div id="msgsent">Your message has been sent</div></html>`), nil
+				})
+		},
+		func(err error) {
+			assert.NoError(t, err)
+		},
+	}, {
+		"mail not confirmed",
+		func() {
+			httpmock.RegisterResponder("POST", sendURL, httpmock.NewStringResponder(200, "<html></html>"))
+		},
+		func(err error) {
+			var sendErr *SendError
+			assert.ErrorAs(t, err, &sendErr)
+			assert.Equal(t, refURL+"/en/writepost?v=3.1&yj=REDACTED&yp=REDACTED", sendErr.URL)
+			assert.NotContains(t, err.Error(), "yptest")
+		},
+	}} {
+		t.Run(s.name, func(t *testing.T) {
+			mockYopmailSetup()
+
+			c, err := New[MailHTMLDoc](context.Background(), Config{})
+			assert.NoError(t, err)
+
+			s.setup()
+			s.test(c.SendMail(context.Background(), "box1", "box1@yopmail.fr", "box2@yopmail.com", "Hi", "Hello world"))
 			httpmock.Reset()
 		})
 	}
@@ -521,6 +763,10 @@ func TestHTTPClientFactoryCreate(t *testing.T) {
 		test  func(*http.Client, error)
 	}
 
+	t.Setenv("HTTP_PROXY", "")
+	t.Setenv("HTTPS_PROXY", "")
+	t.Setenv("YOGO_REQUEST_TIMEOUT", "")
+
 	for _, s := range []scenario{{
 		"0 values",
 		func() {
@@ -614,3 +860,39 @@ func mockYopmailSetup() {
 	httpmock.RegisterResponder("GET", refURL,
 		httpmock.NewStringResponder(200, `<script src="/ver/3.1/webmail.js"></script><input id="yp" value="yptest">`))
 }
+
+func TestNewWithSessionFile(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	mockYopmailSetup()
+	cookies := []string{}
+	httpmock.RegisterResponder("GET", refURL+"/en/inbox?ad=0&ctrl=&d=&id=&login=box1&p=1&r_c=&scrl=&spam=true&v=3.1&yj=ytest&yp=yptest",
+		func(r *http.Request) (*http.Response, error) {
+			cookies = append(cookies, r.Header.Get("Cookie"))
+			res := httpmock.NewStringResponse(200, "w.finrmail(25,2,1,0,0,'alt.zk-4nyqp5l','')")
+			res.Header.Add("Set-Cookie", "yses=abc; Path=/; Max-Age=3600")
+			return res, nil
+		})
+
+	config := Config{SessionFile: filepath.Join(t.TempDir(), "session.json")}
+	for i := 0; i < 2; i++ {
+		c, err := New[MailHTMLDoc](context.Background(), config)
+		assert.NoError(t, err)
+		_, err = c.GetMailsPage(context.Background(), "box1", 1)
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, 1, httpmock.GetCallCountInfo()["GET "+refURL])
+	assert.Equal(t, 1, httpmock.GetCallCountInfo()["GET "+refURL+"/ver/3.1/webmail.js"])
+	assert.Len(t, cookies, 2)
+	assert.NotContains(t, cookies[0], "yses=abc")
+	assert.Contains(t, cookies[1], "yses=abc")
+	assert.Contains(t, cookies[1], "compte=box1")
+
+	sessions, err := LoadSessions(config.SessionFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "yptest", sessions[refURL].YP)
+	assert.Equal(t, "ytest", sessions[refURL].YJ)
+	assert.Equal(t, "3.1", sessions[refURL].APIVersion)
+}
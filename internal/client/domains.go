@@ -0,0 +1,127 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// defaultDomains are the yopmail alias domains known when
+// the list has never been refreshed from the site
+var defaultDomains = []string{
+	"yopmail.com",
+	"yopmail.fr",
+	"yopmail.net",
+	"cool.fr.nf",
+	"jetable.fr.nf",
+	"courriel.fr.nf",
+	"moncourrier.fr.nf",
+	"monemail.fr.nf",
+	"monmail.fr.nf",
+	"hide.biz.st",
+	"mymail.infos.st",
+}
+
+// domainsFile is the content of a domains file, the
+// domains are stored per base URL
+type domainsFile struct {
+	Domains map[string][]string `json:"domains"`
+}
+
+// DefaultDomains returns the yopmail alias domains
+// known when the list has never been refreshed
+func DefaultDomains() []string {
+	return append([]string{}, defaultDomains...)
+}
+
+// GetDomains fetches the alias domains from yopmail,
+// all of them deliver to the same inboxes
+func (c Client[M]) GetDomains(ctx context.Context) ([]string, error) {
+	URL, err := c.decorateURL("domain", true, map[string]string{"d": "all"})
+	if err != nil {
+		return nil, err
+	}
+	doc, err := c.browser.fetchDocument(ctx, "GET", URL, map[string]string{}, nil)
+	if err != nil {
+		return nil, err
+	}
+	domains, err := parseDomains(doc)
+	if err != nil {
+		return nil, &ParseError{Page: "domains", URL: URL, Err: err}
+	}
+	return domains, nil
+}
+
+// parseDomains reads the domains listed as "@domain" options
+func parseDomains(doc *goquery.Document) ([]string, error) {
+	domains := []string{}
+	seen := map[string]bool{}
+	doc.Find("option").Each(func(i int, s *goquery.Selection) {
+		text := strings.TrimSpace(s.Text())
+		if !strings.HasPrefix(text, "@") {
+			return
+		}
+		domain := strings.ToLower(strings.TrimPrefix(text, "@"))
+		if domain != "" && !seen[domain] {
+			seen[domain] = true
+			domains = append(domains, domain)
+		}
+	})
+	if len(domains) == 0 {
+		return nil, errors.New("no domain found")
+	}
+	return domains, nil
+}
+
+// LoadDomains reads the domains of a base URL stored in a domains
+// file, the default domains are returned when none is stored
+func LoadDomains(path string, baseURL string) ([]string, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return DefaultDomains(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var f domainsFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("domains file %s is corrupted : %w", path, err)
+	}
+	if domains := f.Domains[normalizeBaseURL(baseURL)]; len(domains) > 0 {
+		return domains, nil
+	}
+	return DefaultDomains(), nil
+}
+
+// SaveDomains stores the domains of a base URL in a domains file,
+// the domains of the other base URLs are kept
+func SaveDomains(path string, baseURL string, domains []string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	unlock, err := lockFile(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	f := domainsFile{Domains: map[string][]string{}}
+	if b, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(b, &f)
+		if f.Domains == nil {
+			f.Domains = map[string][]string{}
+		}
+	}
+	f.Domains[normalizeBaseURL(baseURL)] = domains
+	b, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomically(path, b)
+}
@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDomains(t *testing.T) {
+	type scenario struct {
+		name  string
+		setup func()
+		test  func([]string, error)
+	}
+
+	domainsURL := refURL + "/en/domain?d=all"
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	for _, s := range []scenario{{
+		"500 when requesting yopmail",
+		func() {
+			httpmock.RegisterResponder("GET", domainsURL, httpmock.NewStringResponder(500, ""))
+		},
+		func(domains []string, err error) {
+			assert.EqualError(t, err, `failure when fetching https://yopmail.com/en/domain?d=all : request failed with error code 500 and body `)
+		},
+	}, {
+		"no domain listed",
+		func() {
+			httpmock.RegisterResponder("GET", domainsURL, httpmock.NewStringResponder(200, "<select><option>Random</option></select>"))
+		},
+		func(domains []string, err error) {
+			var parseErr *ParseError
+			assert.ErrorAs(t, err, &parseErr)
+			assert.EqualError(t, err, "failure when parsing domains : no domain found")
+		},
+	}, {
+		"domains listed",
+		func() {
+			httpmock.RegisterResponder("GET", domainsURL, httpmock.NewStringResponder(200, `<select><option>Random</option><option value="yopmail.com">@yopmail.com</option><option> @YOPmail.fr </option><option>@yopmail.fr</option><option>@</option></select>`))
+		},
+		func(domains []string, err error) {
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"yopmail.com", "yopmail.fr"}, domains)
+		},
+	}} {
+		t.Run(s.name, func(t *testing.T) {
+			mockYopmailSetup()
+
+			c, err := New[MailHTMLDoc](context.Background(), Config{})
+			assert.NoError(t, err)
+
+			s.setup()
+			s.test(c.GetDomains(context.Background()))
+			httpmock.Reset()
+		})
+	}
+}
+
+func TestLoadAndSaveDomains(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "yogo", "domains.json")
+
+	domains, err := LoadDomains(path, "")
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultDomains(), domains)
+
+	assert.NoError(t, SaveDomains(path, "https://yopmail.com/", []string{"yopmail.com", "yopmail.fr"}))
+	assert.NoError(t, SaveDomains(path, "http://127.0.0.1:8025", []string{"yopmail.com"}))
+
+	domains, err = LoadDomains(path, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"yopmail.com", "yopmail.fr"}, domains)
+	domains, err = LoadDomains(path, "http://127.0.0.1:8025/")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"yopmail.com"}, domains)
+	domains, err = LoadDomains(path, "http://127.0.0.1:9000")
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultDomains(), domains)
+
+	assert.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+	_, err = LoadDomains(path, "")
+	assert.ErrorContains(t, err, "is corrupted")
+	assert.NoError(t, SaveDomains(path, "", []string{"yopmail.net"}))
+	domains, err = LoadDomains(path, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"yopmail.net"}, domains)
+}
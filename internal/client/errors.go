@@ -0,0 +1,73 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrCaptcha is returned when yopmail requires a CAPTCHA to be solved
+var ErrCaptcha = errors.New("failure when trying to access content: a CAPTCHA is probably activated, look to the web interface")
+
+// NetworkError is returned when a request could not be sent
+// or when its response could not be received
+type NetworkError struct {
+	URL string
+	Err error
+}
+
+func (e *NetworkError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *NetworkError) Unwrap() error {
+	return e.Err
+}
+
+// HTTPError is returned when yopmail answers with an error status code
+type HTTPError struct {
+	URL        string
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("request failed with error code %d and body %s", e.StatusCode, e.Body)
+}
+
+// ParseError is returned when a page can't be parsed,
+// yopmail has probably changed its layout
+type ParseError struct {
+	// Page is the kind of page parsed
+	Page string
+	URL  string
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("failure when parsing %s : %s", e.Page, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// TokenError is returned when a value required to
+// query yopmail could not be extracted from a page
+type TokenError struct {
+	// Token is the name of the value: api version, yp or yj
+	Token string
+	URL   string
+}
+
+func (e *TokenError) Error() string {
+	return fmt.Sprintf("failure when fetching %s value", e.Token)
+}
+
+// SendError is returned when yopmail doesn't confirm that a mail was sent
+type SendError struct {
+	URL string
+}
+
+func (e *SendError) Error() string {
+	return "failure when sending the mail: yopmail did not confirm it was sent"
+}
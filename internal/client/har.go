@@ -0,0 +1,224 @@
+package client
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// HARRecorder records the HTTP traffic as an HTTP Archive
+// that can be opened in the browser devtools
+type HARRecorder struct {
+	mu      sync.Mutex
+	version string
+	entries []harEntry
+}
+
+type harLog struct {
+	Log struct {
+		Version string     `json:"version"`
+		Creator harCreator `json:"creator"`
+		Entries []harEntry `json:"entries"`
+	} `json:"log"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime time.Time   `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	Error           string      `json:"_error,omitempty"`
+}
+
+type harRequest struct {
+	Method      string       `json:"method"`
+	URL         string       `json:"url"`
+	HTTPVersion string       `json:"httpVersion"`
+	Cookies     []harCookie  `json:"cookies"`
+	Headers     []harNVP     `json:"headers"`
+	QueryString []harNVP     `json:"queryString"`
+	PostData    *harPostData `json:"postData,omitempty"`
+	HeadersSize int          `json:"headersSize"`
+	BodySize    int          `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int         `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []harCookie `json:"cookies"`
+	Headers     []harNVP    `json:"headers"`
+	Content     harContent  `json:"content"`
+	RedirectURL string      `json:"redirectURL"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+}
+
+type harCookie struct {
+	Name     string     `json:"name"`
+	Value    string     `json:"value"`
+	Path     string     `json:"path,omitempty"`
+	Domain   string     `json:"domain,omitempty"`
+	Expires  *time.Time `json:"expires,omitempty"`
+	HTTPOnly bool       `json:"httpOnly,omitempty"`
+	Secure   bool       `json:"secure,omitempty"`
+}
+
+type harNVP struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// NewHARRecorder creates a recorder, version is the
+// version of yogo stored in the archive
+func NewHARRecorder(version string) *HARRecorder {
+	return &HARRecorder{version: version, entries: []harEntry{}}
+}
+
+// record adds an exchange to the archive, res is nil when no response
+// has been received, headersReceived and done are the instants when
+// the response headers and the whole response body were received
+func (h *HARRecorder) record(r *http.Request, requestBody []byte, res *http.Response, responseBody []byte, start time.Time, headersReceived time.Time, done time.Time, err error) {
+	if h == nil {
+		return
+	}
+	entry := harEntry{
+		StartedDateTime: start,
+		Time:            milliseconds(done.Sub(start)),
+		Request: harRequest{
+			Method:      r.Method,
+			URL:         r.URL.String(),
+			HTTPVersion: r.Proto,
+			Cookies:     []harCookie{},
+			Headers:     harNVPs(r.Header),
+			HeadersSize: -1,
+			BodySize:    len(requestBody),
+		},
+		Response: harResponse{
+			Cookies:     []harCookie{},
+			Headers:     []harNVP{},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+		Timings: harTimings{
+			Wait:    milliseconds(headersReceived.Sub(start)),
+			Receive: milliseconds(done.Sub(headersReceived)),
+		},
+	}
+	for _, c := range r.Cookies() {
+		entry.Request.Cookies = append(entry.Request.Cookies, harCookie{Name: c.Name, Value: c.Value})
+	}
+	entry.Request.QueryString = harNVPs(r.URL.Query())
+	if len(requestBody) > 0 {
+		entry.Request.PostData = &harPostData{MimeType: r.Header.Get("Content-Type"), Text: string(requestBody)}
+	}
+	if res != nil {
+		entry.Response.Status = res.StatusCode
+		entry.Response.StatusText = http.StatusText(res.StatusCode)
+		entry.Response.HTTPVersion = res.Proto
+		entry.Response.Headers = harNVPs(res.Header)
+		entry.Response.RedirectURL = res.Header.Get("Location")
+		entry.Response.BodySize = len(responseBody)
+		entry.Response.Content = harContent{Size: len(responseBody), MimeType: res.Header.Get("Content-Type")}
+		if utf8.Valid(responseBody) {
+			entry.Response.Content.Text = string(responseBody)
+		} else {
+			entry.Response.Content.Text = base64.StdEncoding.EncodeToString(responseBody)
+			entry.Response.Content.Encoding = "base64"
+		}
+		for _, c := range res.Cookies() {
+			cookie := harCookie{Name: c.Name, Value: c.Value, Path: c.Path, Domain: c.Domain, HTTPOnly: c.HttpOnly, Secure: c.Secure}
+			if !c.Expires.IsZero() {
+				expires := c.Expires
+				cookie.Expires = &expires
+			}
+			entry.Response.Cookies = append(entry.Response.Cookies, cookie)
+		}
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.entries = append(h.entries, entry)
+}
+
+// WriteTo writes the archive
+func (h *HARRecorder) WriteTo(w io.Writer) (int64, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var l harLog
+	l.Log.Version = "1.2"
+	l.Log.Creator = harCreator{Name: "yogo", Version: h.version}
+	l.Log.Entries = h.entries
+	b, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(b)
+	return int64(n), err
+}
+
+// WriteFile writes the archive in a file
+func (h *HARRecorder) WriteFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := h.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// harNVPs flattens multi-valued pairs sorted by name
+func harNVPs(values map[string][]string) []harNVP {
+	names := []string{}
+	for k := range values {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	nvps := []harNVP{}
+	for _, k := range names {
+		for _, v := range values[k] {
+			nvps = append(nvps, harNVP{Name: k, Value: v})
+		}
+	}
+	return nvps
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
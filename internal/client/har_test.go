@@ -0,0 +1,82 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHARRecorder(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "https://yopmail.com/en/inbox?login=test&yp=abc",
+		func(r *http.Request) (*http.Response, error) {
+			res := httpmock.NewStringResponse(200, "<html></html>")
+			res.Header.Add("Content-Type", "text/html")
+			res.Header.Add("Set-Cookie", "yses=123; Path=/; HttpOnly")
+			return res, nil
+		})
+	httpmock.RegisterResponder("GET", "https://yopmail.com/binary",
+		httpmock.NewBytesResponder(200, []byte{0xff, 0xfe}))
+	httpmock.RegisterResponder("POST", "https://yopmail.com/send",
+		httpmock.NewStringResponder(404, "not found"))
+	httpmock.RegisterResponder("GET", "https://yopmail.com/error",
+		httpmock.NewErrorResponder(errors.New("connection reset")))
+
+	recorder := NewHARRecorder("v4.0.0")
+	b := newBrowser(Config{HAR: recorder})
+	_, err := b.fetch(context.Background(), "GET", "https://yopmail.com/en/inbox?login=test&yp=abc", map[string]string{"Accept": "text/html"}, nil)
+	assert.NoError(t, err)
+	_, err = b.fetch(context.Background(), "GET", "https://yopmail.com/binary", map[string]string{}, nil)
+	assert.NoError(t, err)
+	_, err = b.fetch(context.Background(), "POST", "https://yopmail.com/send", map[string]string{"Content-Type": "application/x-www-form-urlencoded"}, strings.NewReader("msg=hello"))
+	assert.Error(t, err)
+	_, err = b.fetch(context.Background(), "GET", "https://yopmail.com/error", map[string]string{}, nil)
+	assert.Error(t, err)
+
+	var buf bytes.Buffer
+	_, err = recorder.WriteTo(&buf)
+	assert.NoError(t, err)
+	var har harLog
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &har))
+	assert.Equal(t, "1.2", har.Log.Version)
+	assert.Equal(t, harCreator{Name: "yogo", Version: "v4.0.0"}, har.Log.Creator)
+	assert.Len(t, har.Log.Entries, 4)
+
+	e := har.Log.Entries[0]
+	assert.Equal(t, "GET", e.Request.Method)
+	assert.Equal(t, "https://yopmail.com/en/inbox?login=test&yp=abc", e.Request.URL)
+	assert.Equal(t, []harNVP{{"login", "test"}, {"yp", "abc"}}, e.Request.QueryString)
+	assert.Contains(t, e.Request.Headers, harNVP{"Accept", "text/html"})
+	assert.Nil(t, e.Request.PostData)
+	assert.Equal(t, 200, e.Response.Status)
+	assert.Equal(t, "OK", e.Response.StatusText)
+	assert.Equal(t, harContent{Size: 13, MimeType: "text/html", Text: "<html></html>"}, e.Response.Content)
+	assert.Equal(t, []harCookie{{Name: "yses", Value: "123", Path: "/", HTTPOnly: true}}, e.Response.Cookies)
+	assert.GreaterOrEqual(t, e.Time, e.Timings.Wait)
+
+	e = har.Log.Entries[1]
+	assert.Equal(t, harContent{Size: 2, Text: "//4=", Encoding: "base64"}, e.Response.Content)
+
+	e = har.Log.Entries[2]
+	assert.Equal(t, &harPostData{MimeType: "application/x-www-form-urlencoded", Text: "msg=hello"}, e.Request.PostData)
+	assert.Equal(t, 9, e.Request.BodySize)
+	assert.Equal(t, 404, e.Response.Status)
+
+	e = har.Log.Entries[3]
+	assert.Equal(t, 0, e.Response.Status)
+	assert.Contains(t, e.Error, "connection reset")
+
+	path := filepath.Join(t.TempDir(), "yogo.har")
+	assert.NoError(t, recorder.WriteFile(path))
+	assert.FileExists(t, path)
+}
@@ -0,0 +1,172 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseCookies reads cookies exported from a browser and keeps the ones
+// sent to the given base URL, the data is either the value of a Cookie
+// header, a Netscape cookies.txt file or a JSON export as produced by
+// the browser extensions or by Playwright
+func ParseCookies(baseURL string, data string) ([]Cookie, error) {
+	u, err := url.Parse(normalizeBaseURL(baseURL))
+	if err != nil {
+		return nil, err
+	}
+	host := canonicalHost(u)
+	data = strings.TrimSpace(data)
+	var cookies []Cookie
+	switch {
+	case data == "":
+		return nil, errors.New("no cookie provided")
+	case strings.HasPrefix(data, "[") || strings.HasPrefix(data, "{"):
+		cookies, err = parseJSONCookies(data)
+	case isNetscapeCookies(data):
+		cookies, err = parseNetscapeCookies(data)
+	default:
+		cookies = parseCookieHeader(host, data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	matches := []Cookie{}
+	for _, c := range cookies {
+		if c.Name != "" && domainMatch(host, c.Domain) {
+			matches = append(matches, c)
+		}
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no cookie found for %s", host)
+	}
+	return matches, nil
+}
+
+// ImportCookies stores cookies in the session of a base URL persisted in
+// a session file, the session tokens are dropped to be scraped again
+// along with the imported cookies
+func ImportCookies(sessionFile string, baseURL string, cookies []Cookie) error {
+	return updateSession(sessionFile, normalizeBaseURL(baseURL), func(s *SessionState) {
+		j := newJar()
+		j.load(s.Cookies)
+		now := j.now()
+		for _, c := range cookies {
+			j.store(c, now)
+		}
+		*s = SessionState{Cookies: j.all(), UpdatedAt: now}
+	})
+}
+
+func parseCookieHeader(host string, data string) []Cookie {
+	cookies := []Cookie{}
+	data = strings.TrimPrefix(data, "Cookie:")
+	for _, part := range strings.Split(data, ";") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		cookies = append(cookies, Cookie{Name: strings.TrimSpace(name), Value: strings.TrimSpace(value), Domain: host, Path: "/", HostOnly: true})
+	}
+	return cookies
+}
+
+func isNetscapeCookies(data string) bool {
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "# Netscape HTTP Cookie File") || len(strings.Split(strings.TrimPrefix(line, "#HttpOnly_"), "\t")) == 7 {
+			return true
+		}
+	}
+	return false
+}
+
+func parseNetscapeCookies(data string) ([]Cookie, error) {
+	cookies := []Cookie{}
+	for i, line := range strings.Split(data, "\n") {
+		line = strings.TrimRight(line, "\r")
+		line = strings.TrimPrefix(line, "#HttpOnly_")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 7 {
+			return nil, fmt.Errorf("line %d of the cookies file is malformed", i+1)
+		}
+		c := Cookie{
+			Name:     fields[5],
+			Value:    fields[6],
+			Domain:   strings.ToLower(strings.TrimPrefix(fields[0], ".")),
+			Path:     fields[2],
+			Secure:   strings.EqualFold(fields[3], "TRUE"),
+			HostOnly: !strings.EqualFold(fields[1], "TRUE"),
+		}
+		expires, err := strconv.ParseInt(fields[4], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d of the cookies file is malformed : %w", i+1, err)
+		}
+		if expires > 0 {
+			c.Expires = time.Unix(expires, 0).UTC()
+		}
+		cookies = append(cookies, c)
+	}
+	return cookies, nil
+}
+
+// jsonCookie is a cookie exported by a browser extension,
+// expirationDate, or by Playwright, expires
+type jsonCookie struct {
+	Name           string   `json:"name"`
+	Value          string   `json:"value"`
+	Domain         string   `json:"domain"`
+	Path           string   `json:"path"`
+	Secure         bool     `json:"secure"`
+	HostOnly       *bool    `json:"hostOnly"`
+	ExpirationDate *float64 `json:"expirationDate"`
+	Expires        *float64 `json:"expires"`
+}
+
+func parseJSONCookies(data string) ([]Cookie, error) {
+	var exported []jsonCookie
+	if strings.HasPrefix(data, "{") {
+		var state struct {
+			Cookies []jsonCookie `json:"cookies"`
+		}
+		if err := json.Unmarshal([]byte(data), &state); err != nil {
+			return nil, fmt.Errorf("cookies export is malformed : %w", err)
+		}
+		exported = state.Cookies
+	} else if err := json.Unmarshal([]byte(data), &exported); err != nil {
+		return nil, fmt.Errorf("cookies export is malformed : %w", err)
+	}
+	cookies := []Cookie{}
+	for _, e := range exported {
+		c := Cookie{
+			Name:     e.Name,
+			Value:    e.Value,
+			Domain:   strings.ToLower(strings.TrimPrefix(e.Domain, ".")),
+			Path:     e.Path,
+			Secure:   e.Secure,
+			HostOnly: !strings.HasPrefix(e.Domain, "."),
+		}
+		if e.HostOnly != nil {
+			c.HostOnly = *e.HostOnly
+		}
+		if c.Path == "" {
+			c.Path = "/"
+		}
+		for _, expires := range []*float64{e.ExpirationDate, e.Expires} {
+			if expires != nil && *expires > 0 {
+				sec, frac := math.Modf(*expires)
+				c.Expires = time.Unix(int64(sec), int64(frac*1e9)).UTC()
+			}
+		}
+		cookies = append(cookies, c)
+	}
+	return cookies, nil
+}
@@ -0,0 +1,132 @@
+package client
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCookies(t *testing.T) {
+	type scenario struct {
+		name    string
+		baseURL string
+		data    string
+		test    func([]Cookie, error)
+	}
+
+	expires := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, s := range []scenario{
+		{
+			"no data",
+			"",
+			"  ",
+			func(cookies []Cookie, err error) {
+				assert.EqualError(t, err, "no cookie provided")
+			},
+		},
+		{
+			"cookie header value",
+			"",
+			"Cookie: compte=test; yc=abc=def;broken",
+			func(cookies []Cookie, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []Cookie{
+					{Name: "compte", Value: "test", Domain: "yopmail.com", Path: "/", HostOnly: true},
+					{Name: "yc", Value: "abc=def", Domain: "yopmail.com", Path: "/", HostOnly: true},
+				}, cookies)
+			},
+		},
+		{
+			"cookie header value with a base URL",
+			"http://127.0.0.1:8025/",
+			"compte=test",
+			func(cookies []Cookie, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []Cookie{{Name: "compte", Value: "test", Domain: "127.0.0.1", Path: "/", HostOnly: true}}, cookies)
+			},
+		},
+		{
+			"netscape file",
+			"",
+			"# Netscape HTTP Cookie File\n\n.yopmail.com\tTRUE\t/\tTRUE\t1893456000\tyc\tabc\r\n#HttpOnly_yopmail.com\tFALSE\t/en\tFALSE\t0\tyses\t123\nexample.com\tFALSE\t/\tFALSE\t0\tother\t1\n",
+			func(cookies []Cookie, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []Cookie{
+					{Name: "yc", Value: "abc", Domain: "yopmail.com", Path: "/", Expires: expires, Secure: true},
+					{Name: "yses", Value: "123", Domain: "yopmail.com", Path: "/en", HostOnly: true},
+				}, cookies)
+			},
+		},
+		{
+			"malformed netscape file",
+			"",
+			"# Netscape HTTP Cookie File\nyopmail.com\tFALSE\t/\n",
+			func(cookies []Cookie, err error) {
+				assert.EqualError(t, err, "line 2 of the cookies file is malformed")
+			},
+		},
+		{
+			"browser extension export",
+			"",
+			`[{"name":"yc","value":"abc","domain":".yopmail.com","path":"/","secure":true,"hostOnly":false,"expirationDate":1893456000.5},{"name":"yses","value":"123","domain":"yopmail.com","hostOnly":true,"session":true}]`,
+			func(cookies []Cookie, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []Cookie{
+					{Name: "yc", Value: "abc", Domain: "yopmail.com", Path: "/", Expires: expires.Add(500 * time.Millisecond), Secure: true},
+					{Name: "yses", Value: "123", Domain: "yopmail.com", Path: "/", HostOnly: true},
+				}, cookies)
+			},
+		},
+		{
+			"playwright storage state",
+			"",
+			`{"cookies":[{"name":"yc","value":"abc","domain":".yopmail.com","path":"/","expires":-1}],"origins":[]}`,
+			func(cookies []Cookie, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []Cookie{{Name: "yc", Value: "abc", Domain: "yopmail.com", Path: "/"}}, cookies)
+			},
+		},
+		{
+			"malformed JSON export",
+			"",
+			`[{"name":`,
+			func(cookies []Cookie, err error) {
+				assert.ErrorContains(t, err, "cookies export is malformed")
+			},
+		},
+		{
+			"no cookie for yopmail",
+			"",
+			`[{"name":"a","value":"b","domain":"example.com"}]`,
+			func(cookies []Cookie, err error) {
+				assert.EqualError(t, err, "no cookie found for yopmail.com")
+			},
+		},
+	} {
+		t.Run(s.name, func(t *testing.T) {
+			s.test(ParseCookies(s.baseURL, s.data))
+		})
+	}
+}
+
+func TestImportCookies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+	assert.NoError(t, saveSession(path, refURL, SessionState{APIVersion: "9.0", YP: "yp", YJ: "yj", Cookies: []Cookie{
+		{Name: "compte", Value: "test", Domain: "yopmail.com", Path: "/", HostOnly: true},
+		{Name: "yc", Value: "old", Domain: "yopmail.com", Path: "/"},
+	}}))
+
+	assert.NoError(t, ImportCookies(path, "https://yopmail.com/", []Cookie{{Name: "yc", Value: "new", Domain: "yopmail.com", Path: "/"}}))
+
+	sessions, err := LoadSessions(path)
+	assert.NoError(t, err)
+	s := sessions[refURL]
+	assert.Empty(t, s.YP)
+	assert.Empty(t, s.YJ)
+	assert.Equal(t, []Cookie{
+		{Name: "compte", Value: "test", Domain: "yopmail.com", Path: "/", HostOnly: true},
+		{Name: "yc", Value: "new", Domain: "yopmail.com", Path: "/"},
+	}, s.Cookies)
+}
@@ -0,0 +1,172 @@
+package client
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cookie is a cookie stored in the jar
+type Cookie struct {
+	Name     string    `json:"name"`
+	Value    string    `json:"value"`
+	Domain   string    `json:"domain"`
+	Path     string    `json:"path"`
+	Expires  time.Time `json:"expires,omitzero"`
+	Secure   bool      `json:"secure,omitempty"`
+	HostOnly bool      `json:"hostOnly,omitempty"`
+}
+
+// expired tells if the cookie must not be sent anymore,
+// cookies without expiry date live as long as the session
+func (c Cookie) expired(now time.Time) bool {
+	return !c.Expires.IsZero() && !c.Expires.After(now)
+}
+
+// jar is a cookie jar honouring the domain, the path and the
+// expiry date of the cookies, unlike the one of the standard
+// library its content can be exported to be persisted
+type jar struct {
+	mu      sync.Mutex
+	cookies []Cookie
+	now     func() time.Time
+}
+
+func newJar() *jar {
+	return &jar{now: time.Now}
+}
+
+// SetCookies stores the cookies received from the given URL
+func (j *jar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	now := j.now()
+	host := canonicalHost(u)
+	for _, c := range cookies {
+		cookie := Cookie{
+			Name:   c.Name,
+			Value:  c.Value,
+			Domain: strings.ToLower(strings.TrimPrefix(c.Domain, ".")),
+			Path:   c.Path,
+			Secure: c.Secure,
+		}
+		if cookie.Domain == "" {
+			cookie.Domain = host
+			cookie.HostOnly = true
+		}
+		if !domainMatch(host, cookie.Domain) {
+			continue
+		}
+		if !strings.HasPrefix(cookie.Path, "/") {
+			cookie.Path = defaultPath(u)
+		}
+		switch {
+		case c.MaxAge < 0:
+			cookie.Expires = now
+		case c.MaxAge > 0:
+			cookie.Expires = now.Add(time.Duration(c.MaxAge) * time.Second)
+		case !c.Expires.IsZero():
+			cookie.Expires = c.Expires.UTC()
+		}
+		j.store(cookie, now)
+	}
+}
+
+// Cookies returns the cookies to send to the given URL
+func (j *jar) Cookies(u *url.URL) []*http.Cookie {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	now := j.now()
+	host := canonicalHost(u)
+	path := u.Path
+	if path == "" {
+		path = "/"
+	}
+	matches := []Cookie{}
+	for _, c := range j.cookies {
+		if c.expired(now) || (c.Secure && u.Scheme != "https") || !pathMatch(path, c.Path) {
+			continue
+		}
+		if (c.HostOnly && host != c.Domain) || (!c.HostOnly && !domainMatch(host, c.Domain)) {
+			continue
+		}
+		matches = append(matches, c)
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return len(matches[i].Path) > len(matches[j].Path)
+	})
+	cookies := make([]*http.Cookie, 0, len(matches))
+	for _, c := range matches {
+		cookies = append(cookies, &http.Cookie{Name: c.Name, Value: c.Value})
+	}
+	return cookies
+}
+
+// all returns the cookies not expired yet
+func (j *jar) all() []Cookie {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	now := j.now()
+	cookies := []Cookie{}
+	for _, c := range j.cookies {
+		if !c.expired(now) {
+			cookies = append(cookies, c)
+		}
+	}
+	return cookies
+}
+
+// load replaces the content of the jar
+func (j *jar) load(cookies []Cookie) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.cookies = []Cookie{}
+	now := j.now()
+	for _, c := range cookies {
+		j.store(c, now)
+	}
+}
+
+// store adds or replaces a cookie, the lock must be held
+func (j *jar) store(cookie Cookie, now time.Time) {
+	cookies := []Cookie{}
+	for _, c := range j.cookies {
+		if c.Name == cookie.Name && c.Domain == cookie.Domain && c.Path == cookie.Path {
+			continue
+		}
+		cookies = append(cookies, c)
+	}
+	if !cookie.expired(now) {
+		cookies = append(cookies, cookie)
+	}
+	j.cookies = cookies
+}
+
+func canonicalHost(u *url.URL) string {
+	return strings.ToLower(u.Hostname())
+}
+
+func domainMatch(host string, domain string) bool {
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+func pathMatch(path string, cookiePath string) bool {
+	if path == cookiePath {
+		return true
+	}
+	if !strings.HasPrefix(path, cookiePath) {
+		return false
+	}
+	return strings.HasSuffix(cookiePath, "/") || path[len(cookiePath)] == '/'
+}
+
+func defaultPath(u *url.URL) string {
+	i := strings.LastIndex(u.Path, "/")
+	if i <= 0 {
+		return "/"
+	}
+	return u.Path[:i]
+}
@@ -0,0 +1,132 @@
+package client
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJar(t *testing.T) {
+	type scenario struct {
+		name    string
+		set     string
+		cookies []*http.Cookie
+		get     string
+		test    func([]*http.Cookie)
+	}
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, s := range []scenario{
+		{
+			"host only cookie",
+			"https://yopmail.com/en/inbox",
+			[]*http.Cookie{{Name: "a", Value: "1"}},
+			"https://www.yopmail.com/en/mail",
+			func(cookies []*http.Cookie) {
+				assert.Empty(t, cookies)
+			},
+		},
+		{
+			"domain cookie",
+			"https://yopmail.com/",
+			[]*http.Cookie{{Name: "a", Value: "1", Domain: ".yopmail.com"}},
+			"https://www.yopmail.com/en/mail",
+			func(cookies []*http.Cookie) {
+				assert.Equal(t, []*http.Cookie{{Name: "a", Value: "1"}}, cookies)
+			},
+		},
+		{
+			"cookie for another domain",
+			"https://yopmail.com/",
+			[]*http.Cookie{{Name: "a", Value: "1", Domain: "example.com"}},
+			"https://example.com/",
+			func(cookies []*http.Cookie) {
+				assert.Empty(t, cookies)
+			},
+		},
+		{
+			"path",
+			"https://yopmail.com/",
+			[]*http.Cookie{{Name: "a", Value: "1", Path: "/en"}, {Name: "b", Value: "2", Path: "/fr"}, {Name: "c", Value: "3"}},
+			"https://yopmail.com/en/inbox",
+			func(cookies []*http.Cookie) {
+				assert.Equal(t, []*http.Cookie{{Name: "a", Value: "1"}, {Name: "c", Value: "3"}}, cookies)
+			},
+		},
+		{
+			"default path",
+			"https://yopmail.com/en/inbox",
+			[]*http.Cookie{{Name: "a", Value: "1"}},
+			"https://yopmail.com/",
+			func(cookies []*http.Cookie) {
+				assert.Empty(t, cookies)
+			},
+		},
+		{
+			"expiry",
+			"https://yopmail.com/",
+			[]*http.Cookie{{Name: "a", Value: "1", Expires: now.Add(-time.Second)}, {Name: "b", Value: "2", MaxAge: -1}, {Name: "c", Value: "3", Expires: now.Add(time.Hour)}, {Name: "d", Value: "4", MaxAge: 60}},
+			"https://yopmail.com/",
+			func(cookies []*http.Cookie) {
+				assert.Equal(t, []*http.Cookie{{Name: "c", Value: "3"}, {Name: "d", Value: "4"}}, cookies)
+			},
+		},
+		{
+			"secure cookie",
+			"https://yopmail.com/",
+			[]*http.Cookie{{Name: "a", Value: "1", Secure: true}},
+			"http://yopmail.com/",
+			func(cookies []*http.Cookie) {
+				assert.Empty(t, cookies)
+			},
+		},
+		{
+			"cookie replaced",
+			"https://yopmail.com/",
+			[]*http.Cookie{{Name: "a", Value: "1"}, {Name: "a", Value: "2"}},
+			"https://yopmail.com/",
+			func(cookies []*http.Cookie) {
+				assert.Equal(t, []*http.Cookie{{Name: "a", Value: "2"}}, cookies)
+			},
+		},
+	} {
+		t.Run(s.name, func(t *testing.T) {
+			j := newJar()
+			j.now = func() time.Time { return now }
+			u, err := url.Parse(s.set)
+			assert.NoError(t, err)
+			j.SetCookies(u, s.cookies)
+			u, err = url.Parse(s.get)
+			assert.NoError(t, err)
+			s.test(j.Cookies(u))
+		})
+	}
+}
+
+func TestJarExport(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	j := newJar()
+	j.now = func() time.Time { return now }
+	u, err := url.Parse("https://yopmail.com/")
+	assert.NoError(t, err)
+	j.SetCookies(u, []*http.Cookie{{Name: "a", Value: "1", MaxAge: 60}, {Name: "b", Value: "2", Domain: "yopmail.com", Path: "/en"}})
+
+	cookies := j.all()
+	assert.Equal(t, []Cookie{
+		{Name: "a", Value: "1", Domain: "yopmail.com", Path: "/", Expires: now.Add(time.Minute), HostOnly: true},
+		{Name: "b", Value: "2", Domain: "yopmail.com", Path: "/en"},
+	}, cookies)
+
+	j.now = func() time.Time { return now.Add(time.Hour) }
+	assert.Len(t, j.all(), 1)
+
+	other := newJar()
+	other.now = func() time.Time { return now }
+	other.load(cookies)
+	u, err = url.Parse("https://yopmail.com/en/inbox")
+	assert.NoError(t, err)
+	assert.Len(t, other.Cookies(u), 2)
+}
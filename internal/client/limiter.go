@@ -0,0 +1,113 @@
+package client
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimit defines the pace at which requests are sent to yopmail
+type RateLimit struct {
+	// RequestsPerMinute is the sustained rate, 0 disables the limiter
+	RequestsPerMinute int
+	// Burst is the number of requests that can be sent at once
+	Burst int
+}
+
+// DefaultRateLimit returns the rate limit used by
+// default by the command line and the Go API
+func DefaultRateLimit() RateLimit {
+	return RateLimit{
+		RequestsPerMinute: 30,
+		Burst:             5,
+	}
+}
+
+// limiter is a token bucket whose rate is halved every time
+// yopmail shows signs of throttling and that recovers slowly
+// while responses are fine
+type limiter struct {
+	mu      sync.Mutex
+	rate    float64
+	maxRate float64
+	minRate float64
+	burst   float64
+	tokens  float64
+	last    time.Time
+}
+
+func newLimiter(config RateLimit) *limiter {
+	if config.RequestsPerMinute <= 0 {
+		return nil
+	}
+	burst := math.Max(float64(config.Burst), 1)
+	rate := float64(config.RequestsPerMinute) / 60
+	return &limiter{
+		rate:    rate,
+		maxRate: rate,
+		minRate: rate / 8,
+		burst:   burst,
+		tokens:  burst,
+		last:    time.Now(),
+	}
+}
+
+// wait blocks until a request can be sent
+func (l *limiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	for {
+		l.mu.Lock()
+		l.refill()
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+		delay := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		l.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// slowDown halves the rate and empties the bucket
+func (l *limiter) slowDown() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill()
+	l.rate = math.Max(l.rate/2, l.minRate)
+	l.tokens = math.Min(l.tokens, 0)
+}
+
+// speedUp moves the rate back toward the configured one
+func (l *limiter) speedUp() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill()
+	l.rate = math.Min(l.rate+l.maxRate/10, l.maxRate)
+}
+
+// requestsPerMinute returns the current rate
+func (l *limiter) requestsPerMinute() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rate * 60
+}
+
+func (l *limiter) refill() {
+	now := time.Now()
+	l.tokens = math.Min(l.tokens+now.Sub(l.last).Seconds()*l.rate, l.burst)
+	l.last = now
+}
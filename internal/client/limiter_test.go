@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLimiter(t *testing.T) {
+	assert.Nil(t, newLimiter(RateLimit{}))
+	assert.NoError(t, newLimiter(RateLimit{}).wait(context.Background()))
+
+	l := newLimiter(RateLimit{RequestsPerMinute: 60})
+	assert.Equal(t, 1.0, l.rate)
+	assert.Equal(t, 1.0, l.burst)
+}
+
+func TestLimiterWait(t *testing.T) {
+	l := newLimiter(RateLimit{RequestsPerMinute: 1200, Burst: 2})
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, l.wait(context.Background()))
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
+
+func TestLimiterWaitWithCancelledContext(t *testing.T) {
+	l := newLimiter(RateLimit{RequestsPerMinute: 1, Burst: 1})
+	assert.NoError(t, l.wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, l.wait(ctx), context.DeadlineExceeded)
+}
+
+func TestLimiterAdapt(t *testing.T) {
+	l := newLimiter(RateLimit{RequestsPerMinute: 600, Burst: 5})
+
+	l.slowDown()
+	assert.Equal(t, 5.0, l.rate)
+	assert.LessOrEqual(t, l.tokens, 0.1)
+	for i := 0; i < 5; i++ {
+		l.slowDown()
+	}
+	assert.Equal(t, 1.25, l.rate)
+
+	l.speedUp()
+	assert.Equal(t, 2.25, l.rate)
+	for i := 0; i < 20; i++ {
+		l.speedUp()
+	}
+	assert.Equal(t, 10.0, l.rate)
+}
+
+func TestBrowserThrottle(t *testing.T) {
+	b := newBrowser(Config{RateLimit: RateLimit{RequestsPerMinute: 600, Burst: 5}})
+	b.throttle()
+	assert.Equal(t, 5.0, b.limiter.rate)
+}
@@ -0,0 +1,15 @@
+package client
+
+import (
+	"time"
+
+	"github.com/antham/yogo/v4/internal/timezone"
+)
+
+// Location returns the timezone of the dates displayed in the inboxes
+func (c Client[M]) Location() *time.Location {
+	if c.location == nil {
+		return timezone.Yopmail()
+	}
+	return c.location
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const redacted = "REDACTED"
+const maxLoggedBodySize = 2048
+
+// redactedQueryParams are the query parameters carrying the session tokens
+var redactedQueryParams = []string{"yp", "yj"}
+
+var bodyTokenRegexps = []*regexp.Regexp{
+	regexp.MustCompile(`((?:^|[?&'"\s])(?:yp|yj)=)[^&'"\s]+`),
+	regexp.MustCompile(`(<input[^>]*id="yp"[^>]*value=")[^"]*`),
+}
+
+// newLogger returns the logger used for the debug records,
+// they are discarded unless the debug mode is enabled
+func newLogger(config Config) *slog.Logger {
+	switch {
+	case config.Logger != nil:
+		return config.Logger
+	case config.EnableDebugMode:
+		return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	default:
+		return slog.New(slog.DiscardHandler)
+	}
+}
+
+// redactURL hides the session tokens of an URL
+func redactURL(u *url.URL) string {
+	q := u.Query()
+	found := false
+	for _, p := range redactedQueryParams {
+		if q.Has(p) {
+			q.Set(p, redacted)
+			found = true
+		}
+	}
+	if !found {
+		return u.String()
+	}
+	r := *u
+	r.RawQuery = q.Encode()
+	return r.String()
+}
+
+func redactURLString(URL string) string {
+	u, err := url.Parse(URL)
+	if err != nil {
+		return URL
+	}
+	return redactURL(u)
+}
+
+// redactHeaders hides the cookie values, the cookie
+// names are kept as they help to debug
+func redactHeaders(headers http.Header) map[string]string {
+	h := map[string]string{}
+	for k, values := range headers {
+		switch k {
+		case "Cookie":
+			names := []string{}
+			for _, v := range values {
+				for _, c := range strings.Split(v, ";") {
+					name, _, _ := strings.Cut(strings.TrimSpace(c), "=")
+					names = append(names, name+"="+redacted)
+				}
+			}
+			h[k] = strings.Join(names, "; ")
+		case "Set-Cookie":
+			names := []string{}
+			for _, v := range values {
+				name, _, _ := strings.Cut(v, "=")
+				names = append(names, name+"="+redacted)
+			}
+			h[k] = strings.Join(names, ", ")
+		default:
+			h[k] = strings.Join(values, ", ")
+		}
+	}
+	return h
+}
+
+// redactBody hides the session tokens of a body and truncates it
+func redactBody(body []byte) string {
+	s := string(body)
+	for _, r := range bodyTokenRegexps {
+		s = r.ReplaceAllString(s, "${1}"+redacted)
+	}
+	if len(s) > maxLoggedBodySize {
+		// the body is cut on a rune boundary to keep the log valid UTF-8
+		end := maxLoggedBodySize
+		for end > 0 && !utf8.RuneStart(s[end]) {
+			end--
+		}
+		return fmt.Sprintf("%s... (%d bytes truncated)", s[:end], len(s)-end)
+	}
+	return s
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactURL(t *testing.T) {
+	for _, s := range []struct {
+		URL      string
+		expected string
+	}{
+		{"https://yopmail.com", "https://yopmail.com"},
+		{"https://yopmail.com/en/mail?b=test&id=me_abc", "https://yopmail.com/en/mail?b=test&id=me_abc"},
+		{"https://yopmail.com/en/inbox?login=test&v=4.8&yj=secret1&yp=secret2", "https://yopmail.com/en/inbox?login=test&v=4.8&yj=REDACTED&yp=REDACTED"},
+	} {
+		u, err := url.Parse(s.URL)
+		assert.NoError(t, err)
+		assert.Equal(t, s.expected, redactURL(u))
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	assert.Equal(t, map[string]string{
+		"Cookie":     "compte=REDACTED; ywm=REDACTED",
+		"Set-Cookie": "yses=REDACTED, ytime=REDACTED",
+		"User-Agent": "yogo",
+	}, redactHeaders(http.Header{
+		"Cookie":     {"compte=test; ywm=test"},
+		"Set-Cookie": {"yses=abc; Path=/", "ytime=10:10"},
+		"User-Agent": {"yogo"},
+	}))
+}
+
+func TestRedactBody(t *testing.T) {
+	type scenario struct {
+		name     string
+		body     string
+		expected string
+	}
+
+	for _, s := range []scenario{
+		{
+			"yp input",
+			`<input type="hidden" name="yp" id="yp" value="secret">`,
+			`<input type="hidden" name="yp" id="yp" value="REDACTED">`,
+		},
+		{
+			"yj in javascript",
+			`var u='inbox?login='+l+'&yp='+yp+'&yj=secret&v=4.8';`,
+			`var u='inbox?login='+l+'&yp='+yp+'&yj=REDACTED&v=4.8';`,
+		},
+		{
+			"truncated body",
+			strings.Repeat("a", maxLoggedBodySize+10),
+			strings.Repeat("a", maxLoggedBodySize) + "... (10 bytes truncated)",
+		},
+		{
+			"truncated body on a rune boundary",
+			strings.Repeat("a", maxLoggedBodySize-1) + "éé",
+			strings.Repeat("a", maxLoggedBodySize-1) + "... (4 bytes truncated)",
+		},
+	} {
+		t.Run(s.name, func(t *testing.T) {
+			assert.Equal(t, s.expected, redactBody([]byte(s.body)))
+		})
+	}
+}
+
+func TestFetchLogs(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "https://yopmail.com/en/inbox?login=test&yj=secret1&yp=secret2",
+		func(r *http.Request) (*http.Response, error) {
+			res := httpmock.NewStringResponse(200, `<input id="yp" value="secret3">`)
+			res.Header.Add("Set-Cookie", "yses=secret4")
+			return res, nil
+		})
+
+	var output bytes.Buffer
+	b := newBrowser(Config{Logger: slog.New(slog.NewTextHandler(&output, &slog.HandlerOptions{Level: slog.LevelDebug}))})
+	u, err := url.Parse("https://yopmail.com")
+	assert.NoError(t, err)
+	b.jar.SetCookies(u, []*http.Cookie{{Name: "compte", Value: "secret5"}})
+	_, err = b.fetch(context.Background(), "GET", "https://yopmail.com/en/inbox?login=test&yj=secret1&yp=secret2", map[string]string{}, nil)
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "msg=request")
+	assert.Contains(t, lines[0], "compte=REDACTED")
+	assert.Contains(t, lines[1], "msg=response")
+	assert.Contains(t, lines[1], "status=200")
+	assert.Contains(t, lines[1], "duration=")
+	assert.Contains(t, lines[1], "yses=REDACTED")
+	assert.NotContains(t, output.String(), "secret")
+	ID := strings.Fields(lines[0][strings.Index(lines[0], "id="):])[0]
+	assert.Contains(t, lines[1], ID)
+}
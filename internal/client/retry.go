@@ -0,0 +1,96 @@
+package client
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy defines how requests failing because of
+// a transient error are attempted again
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts made for a request
+	MaxAttempts int
+	// BaseDelay is the delay before the second attempt, it doubles
+	// on every following attempt
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between two attempts,
+	// including the one requested with Retry-After
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy returns the policy used by the command line
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+	}
+}
+
+// backoff computes the delay to wait after the given failed attempt,
+// the exponential delay is jittered to not hammer the server
+// in lockstep, a Retry-After value sent by the server wins
+// if it is longer, it is capped by MaxDelay as well
+func (r RetryPolicy) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	delay := r.BaseDelay << (attempt - 1)
+	if delay <= 0 || (r.MaxDelay > 0 && delay > r.MaxDelay) {
+		delay = r.MaxDelay
+	}
+	if delay > 1 {
+		delay = delay/2 + rand.N(delay/2)
+	}
+	if r.MaxDelay > 0 && retryAfter > r.MaxDelay {
+		retryAfter = r.MaxDelay
+	}
+	if retryAfter > delay {
+		return retryAfter
+	}
+	return delay
+}
+
+// retryableError marks a failure that may succeed on another attempt
+type retryableError struct {
+	err        error
+	retryAfter time.Duration
+}
+
+func (r *retryableError) Error() string {
+	return r.err.Error()
+}
+
+func (r *retryableError) Unwrap() error {
+	return r.err
+}
+
+func isIdempotent(method string) bool {
+	switch strings.ToUpper(method) {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+func isRetryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= 500
+}
+
+// parseRetryAfter reads a Retry-After header expressed
+// either in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchWithRetry(t *testing.T) {
+	type scenario struct {
+		name   string
+		method string
+		setup  func()
+		test   func(error)
+	}
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	for _, s := range []scenario{{
+		"a transient error followed by a success",
+		"GET",
+		func() {
+			httpmock.RegisterResponder("GET", "http://hijklm.com",
+				httpmock.ResponderFromMultipleResponses([]*http.Response{
+					httpmock.NewStringResponse(503, ""),
+					httpmock.NewStringResponse(200, "ok"),
+				}))
+		}, func(err error) {
+			assert.NoError(t, err)
+			assert.Equal(t, 2, httpmock.GetTotalCallCount())
+		},
+	}, {
+		"a connection error followed by a success",
+		"GET",
+		func() {
+			calls := 0
+			httpmock.RegisterResponder("GET", "http://hijklm.com",
+				func(r *http.Request) (*http.Response, error) {
+					calls++
+					if calls == 1 {
+						return nil, errors.New("connection reset by peer")
+					}
+					return httpmock.NewStringResponse(200, "ok"), nil
+				})
+		}, func(err error) {
+			assert.NoError(t, err)
+			assert.Equal(t, 2, httpmock.GetTotalCallCount())
+		},
+	}, {
+		"too many requests on every attempt",
+		"GET",
+		func() {
+			httpmock.RegisterResponder("GET", "http://hijklm.com",
+				httpmock.NewStringResponder(429, "slow down"))
+		}, func(err error) {
+			assert.EqualError(t, err, "failure when fetching http://hijklm.com after 3 attempts : request failed with error code 429 and body slow down")
+			assert.Equal(t, 3, httpmock.GetTotalCallCount())
+		},
+	}, {
+		"a client error is not retried",
+		"GET",
+		func() {
+			httpmock.RegisterResponder("GET", "http://hijklm.com",
+				httpmock.NewStringResponder(404, ""))
+		}, func(err error) {
+			assert.EqualError(t, err, "failure when fetching http://hijklm.com : request failed with error code 404 and body ")
+			assert.Equal(t, 1, httpmock.GetTotalCallCount())
+		},
+	}, {
+		"a non idempotent request is not retried",
+		"POST",
+		func() {
+			httpmock.RegisterResponder("POST", "http://hijklm.com",
+				httpmock.NewStringResponder(500, ""))
+		}, func(err error) {
+			assert.EqualError(t, err, "failure when fetching http://hijklm.com : request failed with error code 500 and body ")
+			assert.Equal(t, 1, httpmock.GetTotalCallCount())
+		},
+	}} {
+		t.Run(s.name, func(t *testing.T) {
+			b := newBrowser(Config{Retry: RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}})
+			s.setup()
+			_, err := b.fetch(context.Background(), s.method, "http://hijklm.com", map[string]string{}, nil)
+			s.test(err)
+			httpmock.Reset()
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for attempt, max := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 400 * time.Millisecond, 5: time.Second, 70: time.Second} {
+		delay := policy.backoff(attempt, 0)
+		assert.GreaterOrEqual(t, delay, max/2)
+		assert.LessOrEqual(t, delay, max)
+	}
+	assert.Equal(t, 800*time.Millisecond, policy.backoff(1, 800*time.Millisecond))
+	assert.Equal(t, time.Second, policy.backoff(1, time.Hour))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2023, 8, 13, 22, 45, 0, 0, time.UTC)
+
+	for value, expected := range map[string]time.Duration{
+		"":                              0,
+		"12":                            12 * time.Second,
+		"-1":                            0,
+		"whatever":                      0,
+		"Sun, 13 Aug 2023 22:45:30 GMT": 30 * time.Second,
+		"Sun, 13 Aug 2023 22:44:30 GMT": 0,
+	} {
+		assert.Equal(t, expected, parseRetryAfter(value, now), value)
+	}
+}
@@ -0,0 +1,157 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const lockTimeout = 5 * time.Second
+const lockRetryDelay = 50 * time.Millisecond
+const staleLockAge = 30 * time.Second
+
+// SessionState is the part of a session persisted between two runs
+type SessionState struct {
+	APIVersion string    `json:"apiVersion"`
+	YP         string    `json:"yp"`
+	YJ         string    `json:"yj"`
+	Cookies    []Cookie  `json:"cookies"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// stateFile is the content of a session file, sessions
+// are stored per base URL
+type stateFile struct {
+	Sessions map[string]SessionState `json:"sessions"`
+}
+
+// DefaultSessionFile returns the path of the session
+// file in the cache directory of the user
+func DefaultSessionFile() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "yogo", "session.json"), nil
+}
+
+// LoadSessions reads the sessions stored in a session file indexed
+// by base URL, a missing file is an empty one
+func LoadSessions(path string) (map[string]SessionState, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]SessionState{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state stateFile
+	if err := json.Unmarshal(b, &state); err != nil {
+		return nil, fmt.Errorf("session file %s is corrupted : %w", path, err)
+	}
+	if state.Sessions == nil {
+		state.Sessions = map[string]SessionState{}
+	}
+	return state.Sessions, nil
+}
+
+// ClearSessions removes a session file
+func ClearSessions(path string) error {
+	if _, err := os.Stat(filepath.Dir(path)); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	unlock, err := lockFile(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// saveSession stores the session of a base URL in a session file,
+// the sessions of the other base URLs are kept
+func saveSession(path string, baseURL string, s SessionState) error {
+	return updateSession(path, baseURL, func(state *SessionState) {
+		*state = s
+	})
+}
+
+// updateSession modifies the session of a base URL stored in a session
+// file while holding the lock, a corrupted file is replaced
+func updateSession(path string, baseURL string, update func(*SessionState)) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	unlock, err := lockFile(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	sessions, err := LoadSessions(path)
+	if err != nil {
+		sessions = map[string]SessionState{}
+	}
+	s := sessions[baseURL]
+	update(&s)
+	sessions[baseURL] = s
+	b, err := json.MarshalIndent(stateFile{Sessions: sessions}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomically(path, b)
+}
+
+// writeFileAtomically writes a temporary file renamed afterward,
+// a reader never sees a partially written file
+func writeFileAtomically(path string, b []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// lockFile acquires an exclusive lock on a file shared between
+// processes, a lock file is used as it works on every platform,
+// a lock older than staleLockAge is considered abandoned
+func lockFile(path string) (func(), error) {
+	lock := path + ".lock"
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(lock, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lock) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, err
+		}
+		if info, err := os.Stat(lock); err == nil && time.Since(info.ModTime()) > staleLockAge {
+			os.Remove(lock)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("file %s is locked by another process, remove %s if it is not the case", path, lock)
+		}
+		time.Sleep(lockRetryDelay)
+	}
+}
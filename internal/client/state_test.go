@@ -0,0 +1,93 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadSessions(t *testing.T) {
+	type scenario struct {
+		name    string
+		content string
+		test    func(map[string]SessionState, error)
+	}
+
+	for _, s := range []scenario{
+		{
+			"missing file",
+			"",
+			func(sessions map[string]SessionState, err error) {
+				assert.NoError(t, err)
+				assert.Empty(t, sessions)
+			},
+		},
+		{
+			"corrupted file",
+			"{",
+			func(sessions map[string]SessionState, err error) {
+				assert.ErrorContains(t, err, "is corrupted")
+			},
+		},
+		{
+			"sessions",
+			`{"sessions":{"https://yopmail.com":{"apiVersion":"9.0","yp":"yp","yj":"yj"}}}`,
+			func(sessions map[string]SessionState, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, map[string]SessionState{"https://yopmail.com": {APIVersion: "9.0", YP: "yp", YJ: "yj"}}, sessions)
+			},
+		},
+	} {
+		t.Run(s.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "session.json")
+			if s.content != "" {
+				assert.NoError(t, os.WriteFile(path, []byte(s.content), 0o600))
+			}
+			s.test(LoadSessions(path))
+		})
+	}
+}
+
+func TestSaveAndClearSessions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "yogo", "session.json")
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, saveSession(path, "https://yopmail.com", SessionState{APIVersion: "9.0", YP: "yp", YJ: "yj", UpdatedAt: date}))
+	assert.NoError(t, saveSession(path, "http://127.0.0.1:8025", SessionState{APIVersion: "1.0", YP: "yp2", YJ: "yj2", UpdatedAt: date}))
+	assert.NoError(t, saveSession(path, "https://yopmail.com", SessionState{APIVersion: "9.1", YP: "yp3", YJ: "yj3", Cookies: []Cookie{{Name: "a", Value: "1", Domain: "yopmail.com", Path: "/"}}, UpdatedAt: date}))
+
+	sessions, err := LoadSessions(path)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]SessionState{
+		"https://yopmail.com":   {APIVersion: "9.1", YP: "yp3", YJ: "yj3", Cookies: []Cookie{{Name: "a", Value: "1", Domain: "yopmail.com", Path: "/"}}, UpdatedAt: date},
+		"http://127.0.0.1:8025": {APIVersion: "1.0", YP: "yp2", YJ: "yj2", UpdatedAt: date},
+	}, sessions)
+	entries, err := os.ReadDir(filepath.Dir(path))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	assert.NoError(t, ClearSessions(path))
+	assert.NoFileExists(t, path)
+	assert.NoError(t, ClearSessions(path))
+	assert.NoError(t, ClearSessions(filepath.Join(t.TempDir(), "missing", "session.json")))
+}
+
+func TestLockFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+
+	unlock, err := lockFile(path)
+	assert.NoError(t, err)
+	assert.FileExists(t, path+".lock")
+	unlock()
+	assert.NoFileExists(t, path+".lock")
+
+	assert.NoError(t, os.WriteFile(path+".lock", nil, 0o600))
+	stale := time.Now().Add(-2 * staleLockAge)
+	assert.NoError(t, os.Chtimes(path+".lock", stale, stale))
+	unlock, err = lockFile(path)
+	assert.NoError(t, err)
+	unlock()
+}
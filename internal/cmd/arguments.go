@@ -3,18 +3,22 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
-	"strings"
+
+	"github.com/antham/yogo/v4/internal/address"
+	"github.com/antham/yogo/v4/internal/client"
+	"github.com/antham/yogo/v4/internal/inbox"
 )
 
 func parseOffset(offset string) (int, error) {
 	offsetInt, err := strconv.Atoi(offset)
 	if err != nil {
-		return 0, fmt.Errorf(`offset "%s" must be an integer`, offset)
+		return 0, &inbox.OffsetError{Offset: offset, Reason: "must be an integer"}
 	}
 
 	if offsetInt < 1 {
-		return 0, fmt.Errorf(`offset "%d" must be greater than 0`, offsetInt)
+		return 0, &inbox.OffsetError{Offset: strconv.Itoa(offsetInt), Reason: "must be greater than 0"}
 	}
 
 	// Providing an uppercased email triggers a panic.
@@ -24,21 +28,66 @@ func parseOffset(offset string) (int, error) {
 	return offsetInt, nil
 }
 
-func normalizeInboxName(inboxName string) string {
-	// Providing an uppercased email triggers a panic.
-	// In the web interface there is a redirection to
-	// the inbox with the address lowercased so we mimic
-	// this behaviour
-	return strings.ReplaceAll(strings.ToLower(inboxName), "@yopmail.com", "")
+// normalizeInboxName returns the login of an inbox from its name or
+// from its address on any of the yopmail alias domains
+func normalizeInboxName(inboxName string) (string, error) {
+	login, _, err := normalizeInbox(inboxName)
+	return login, err
 }
 
-func checkOffset(count int, offset int) error {
-	if count < offset-1 {
-		return errors.New("lower your offset value")
+// normalizeInbox returns the login and the address of an inbox,
+// the domain of the address is kept when one is provided
+func normalizeInbox(inboxName string) (string, string, error) {
+	login, addr, err := address.Normalize(inboxName, knownDomains())
+	var domainErr *address.DomainError
+	if errors.As(err, &domainErr) {
+		return "", "", domainError(domainErr)
 	}
+	return login, addr, err
+}
+
+// domainError suggests to list the yopmail domains when one is rejected
+func domainError(err *address.DomainError) error {
+	return fmt.Errorf(`%w, run "yogo domains" to list them`, err)
+}
 
+func checkOffset(count int, offset int) error {
 	if count == 0 {
-		return errors.New("inbox is empty")
+		return inbox.ErrEmptyInbox
+	}
+
+	if count < offset {
+		return &inbox.OffsetError{Offset: strconv.Itoa(offset), Reason: fmt.Sprintf("is greater than the number of mails (%d), lower your offset value", count)}
 	}
 	return nil
 }
+
+// normalizeRecipient turns an inbox name into its address, yopmail
+// only delivers the mails sent from an inbox to yopmail addresses
+func normalizeRecipient(recipient string) (string, error) {
+	name, err := normalizeInboxName(recipient)
+	if err != nil {
+		return "", err
+	}
+	return name + "@" + address.DefaultDomain, nil
+}
+
+// knownDomains returns the yopmail alias domains, the ones refreshed
+// with "yogo domains --refresh" are added to the default ones
+func knownDomains() []string {
+	domains := client.DefaultDomains()
+	path, err := domainsFilePath()
+	if err != nil {
+		return domains
+	}
+	refreshed, err := client.LoadDomains(path, baseURL)
+	if err != nil {
+		return domains
+	}
+	for _, domain := range refreshed {
+		if !slices.Contains(domains, domain) {
+			domains = append(domains, domain)
+		}
+	}
+	return domains
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/antham/yogo/v4/internal/address"
+	"github.com/antham/yogo/v4/internal/inbox"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -41,6 +43,8 @@ func TestParseOffset(t *testing.T) {
 			offset, err := parseOffset(scenario.offsetArg)
 			if scenario.err != nil {
 				assert.EqualError(t, err, scenario.err.Error())
+				var offsetErr *inbox.OffsetError
+				assert.ErrorAs(t, err, &offsetErr)
 			} else {
 				assert.Equal(t, scenario.offsetExpected, offset)
 			}
@@ -53,6 +57,7 @@ func TestNormalizeInboxName(t *testing.T) {
 		name          string
 		inboxArg      string
 		inboxExpected string
+		err           error
 	}
 
 	scenarios := []scenario{
@@ -66,6 +71,21 @@ func TestNormalizeInboxName(t *testing.T) {
 			inboxArg:      "test@yopmail.com",
 			inboxExpected: "test",
 		},
+		{
+			name:          "email on an alias domain",
+			inboxArg:      "Test@Cool.fr.nf",
+			inboxExpected: "test",
+		},
+		{
+			name:     "email on a foreign domain",
+			inboxArg: "test@example.com",
+			err:      domainError(&address.DomainError{Domain: "example.com"}),
+		},
+		{
+			name:     "empty name",
+			inboxArg: "@yopmail.com",
+			err:      errors.New(`inbox "@yopmail.com" is not a valid inbox name`),
+		},
 	}
 
 	for _, scenario := range scenarios {
@@ -73,7 +93,8 @@ func TestNormalizeInboxName(t *testing.T) {
 		t.Run(scenario.name, func(t *testing.T) {
 			t.Parallel()
 
-			inbox := normalizeInboxName(scenario.inboxArg)
+			inbox, err := normalizeInboxName(scenario.inboxArg)
+			assert.Equal(t, scenario.err, err)
 			assert.Equal(t, scenario.inboxExpected, inbox)
 		})
 	}
@@ -88,22 +109,73 @@ func TestCheckOffset(t *testing.T) {
 
 	scenarios := []scenario{
 		{
-			name: "second argument as string",
-			args: []int{1, 3},
-			err:  errors.New(`lower your offset value`),
+			name: "offset greater than the number of mails",
+			args: []int{1, 2},
+			err:  &inbox.OffsetError{Offset: "2", Reason: "is greater than the number of mails (1), lower your offset value"},
 		},
 		{
-			name: "regular offset",
+			name: "empty inbox",
 			args: []int{0, 1},
+			err:  inbox.ErrEmptyInbox,
+		},
+		{
+			name: "regular offset",
+			args: []int{2, 2},
 		},
 	}
 
 	for _, scenario := range scenarios {
 		t.Run(scenario.name, func(t *testing.T) {
 			err := checkOffset(scenario.args[0], scenario.args[1])
-			if scenario.err != nil {
-				assert.EqualError(t, err, scenario.err.Error())
-			}
+			assert.Equal(t, scenario.err, err)
+		})
+	}
+}
+
+func TestNormalizeRecipient(t *testing.T) {
+	type scenario struct {
+		name              string
+		recipientArg      string
+		recipientExpected string
+		err               error
+	}
+
+	scenarios := []scenario{
+		{
+			name:              "inbox name",
+			recipientArg:      "TeSt",
+			recipientExpected: "test@yopmail.com",
+		},
+		{
+			name:              "full email provided",
+			recipientArg:      " Test@YOPmail.com ",
+			recipientExpected: "test@yopmail.com",
+		},
+		{
+			name:              "email on an alias domain",
+			recipientArg:      "test@yopmail.fr",
+			recipientExpected: "test@yopmail.com",
+		},
+		{
+			name:         "address outside yopmail",
+			recipientArg: "test@example.com",
+			err:          domainError(&address.DomainError{Domain: "example.com"}),
+		},
+		{
+			name:         "empty name",
+			recipientArg: "@yopmail.com",
+			err:          errors.New(`inbox "@yopmail.com" is not a valid inbox name`),
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario
+		t.Run(scenario.name, func(t *testing.T) {
+			t.Parallel()
+
+			recipient, err := normalizeRecipient(scenario.recipientArg)
+			assert.Equal(t, scenario.err, err)
+			assert.Equal(t, scenario.recipientExpected, recipient)
 		})
 	}
 }
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/antham/yogo/v4/internal/client"
+	"github.com/spf13/cobra"
+)
+
+var captchaCookies = ""
+var captchaCookiesFile = ""
+
+var captchaCmd = &cobra.Command{
+	Use:   "captcha <inbox>",
+	Short: "Resume the session from a CAPTCHA solved in a browser",
+	Long: `Resume the session from a CAPTCHA solved in a browser.
+
+Open the printed URL in a browser, solve the CAPTCHA, then provide the
+yopmail cookies of the browser either:
+  - with --cookies, the value of the Cookie header of a request sent to yopmail
+  - with --cookies-file, a Netscape cookies.txt file or a JSON cookie export
+  - on the standard input, in any of the formats above, ended with Ctrl-D
+
+The cookies are stored in the session file and used by the next runs,
+yopmail may bind them to the browser so set YOGO_USER_AGENT to the
+user agent of the browser if the CAPTCHA keeps coming back.`,
+	RunE: captcha,
+	Args: cobra.ExactArgs(1),
+}
+
+func captcha(cmd *cobra.Command, args []string) error {
+	path, err := sessionFilePath()
+	if err != nil {
+		return err
+	}
+	identifier, err := normalizeInboxName(args[0])
+	if err != nil {
+		return err
+	}
+	cmd.Println(info(fmt.Sprintf("Open %s in a browser to solve the CAPTCHA", client.InboxURL(baseURL, identifier))))
+	data := captchaCookies
+	switch {
+	case captchaCookiesFile != "":
+		b, err := os.ReadFile(captchaCookiesFile)
+		if err != nil {
+			return err
+		}
+		data = string(b)
+	case data == "":
+		cmd.Println(info("Paste the cookies of the browser then press Ctrl-D"))
+		b, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return err
+		}
+		data = string(b)
+	}
+	cookies, err := client.ParseCookies(baseURL, data)
+	if err != nil {
+		return err
+	}
+	if err := client.ImportCookies(path, baseURL, cookies); err != nil {
+		return err
+	}
+	cmd.Println(success(fmt.Sprintf(`%d cookies imported in session "%s"`, len(cookies), path)))
+	return nil
+}
+
+func init() {
+	captchaCmd.Flags().StringVar(&captchaCookies, "cookies", "", "Value of the Cookie header sent by the browser")
+	captchaCmd.Flags().StringVar(&captchaCookiesFile, "cookies-file", "", "Netscape cookies.txt file or JSON cookie export")
+	RootCmd.AddCommand(captchaCmd)
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/antham/yogo/v4/internal/client"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCaptcha(t *testing.T) {
+	type scenario struct {
+		name        string
+		cookies     string
+		cookiesFile string
+		stdin       string
+		test        func(output string, path string, err error)
+	}
+
+	openMsg := "Open https://yopmail.com/en/?login=test in a browser to solve the CAPTCHA\n"
+	dir := t.TempDir()
+	netscape := filepath.Join(dir, "cookies.txt")
+	assert.NoError(t, os.WriteFile(netscape, []byte("# Netscape HTTP Cookie File\n.yopmail.com\tTRUE\t/\tFALSE\t0\tyc\tabc\n"), 0o600))
+
+	for _, s := range []scenario{
+		{
+			"cookie header value",
+			"compte=test; yc=abc",
+			"",
+			"",
+			func(output string, path string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, openMsg+`2 cookies imported in session "`+path+`"`+"\n", output)
+				sessions, err := client.LoadSessions(path)
+				assert.NoError(t, err)
+				assert.Len(t, sessions["https://yopmail.com"].Cookies, 2)
+			},
+		},
+		{
+			"cookies file",
+			"",
+			netscape,
+			"",
+			func(output string, path string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, openMsg+`1 cookies imported in session "`+path+`"`+"\n", output)
+			},
+		},
+		{
+			"standard input",
+			"",
+			"",
+			"yc=abc\n",
+			func(output string, path string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, openMsg+"Paste the cookies of the browser then press Ctrl-D\n"+`1 cookies imported in session "`+path+`"`+"\n", output)
+			},
+		},
+		{
+			"missing cookies file",
+			"",
+			filepath.Join(dir, "missing.txt"),
+			"",
+			func(output string, path string, err error) {
+				assert.ErrorIs(t, err, os.ErrNotExist)
+			},
+		},
+		{
+			"no cookie",
+			"",
+			"",
+			"",
+			func(output string, path string, err error) {
+				assert.EqualError(t, err, "no cookie provided")
+				assert.NoFileExists(t, path)
+			},
+		},
+	} {
+		t.Run(s.name, func(t *testing.T) {
+			sessionFile = filepath.Join(t.TempDir(), "session.json")
+			captchaCookies = s.cookies
+			captchaCookiesFile = s.cookiesFile
+			defer func() {
+				sessionFile = ""
+				captchaCookies = ""
+				captchaCookiesFile = ""
+			}()
+
+			var output bytes.Buffer
+			cmd := &cobra.Command{}
+			cmd.SetOut(&output)
+			cmd.SetIn(strings.NewReader(s.stdin))
+			err := captcha(cmd, []string{"Test@yopmail.com"})
+			s.test(output.String(), sessionFile, err)
+		})
+	}
+}
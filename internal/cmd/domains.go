@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/antham/yogo/v4/internal/client"
+	"github.com/spf13/cobra"
+)
+
+type domainsFetcher func(context.Context) ([]string, error)
+
+var domainsRefresh = false
+
+var domainsCmd = &cobra.Command{
+	Use:   "domains",
+	Short: "List the yopmail alias domains",
+	Long: `List the yopmail alias domains.
+
+All of them deliver to the same inboxes: test@yopmail.fr is the inbox
+test, pick the one accepted by a signup form. The list is refreshed
+from yopmail with --refresh and cached next to the session file.`,
+	RunE: domains(fetchDomains),
+	Args: cobra.NoArgs,
+}
+
+func domains(fetcher domainsFetcher) cobraCmd {
+	return func(cmd *cobra.Command, args []string) error {
+		path, err := domainsFilePath()
+		if err != nil {
+			return err
+		}
+		var list []string
+		if domainsRefresh {
+			list, err = fetcher(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if err := client.SaveDomains(path, baseURL, list); err != nil {
+				return err
+			}
+		} else {
+			list, err = client.LoadDomains(path, baseURL)
+			if err != nil {
+				return err
+			}
+		}
+
+		if dumpJSON {
+			b, err := json.Marshal(struct {
+				Domains []string `json:"domains"`
+			}{list})
+			if err != nil {
+				return err
+			}
+			cmd.Println(string(b))
+			return nil
+		}
+		cmd.Println(info(strings.Join(list, "\n")))
+		return nil
+	}
+}
+
+func fetchDomains(ctx context.Context) ([]string, error) {
+	config, err := clientConfig()
+	if err != nil {
+		return nil, err
+	}
+	c, err := client.New[client.MailHTMLDoc](ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetDomains(ctx)
+}
+
+func init() {
+	domainsCmd.Flags().BoolVar(&domainsRefresh, "refresh", false, "Fetch the domains from yopmail and cache them")
+	RootCmd.AddCommand(domainsCmd)
+}
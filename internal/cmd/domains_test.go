@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/antham/yogo/v4/internal/client"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomains(t *testing.T) {
+	type scenario struct {
+		name     string
+		refresh  bool
+		json     bool
+		fetcher  domainsFetcher
+		err      error
+		output   string
+		domains  []string
+		known    []string
+		inboxArg string
+	}
+
+	scenarios := []scenario{
+		{
+			name:    "Default domains",
+			output:  strings.Join(client.DefaultDomains(), "\n") + "\n",
+			domains: client.DefaultDomains(),
+			known:   client.DefaultDomains(),
+		},
+		{
+			name:    "Refresh failure",
+			refresh: true,
+			fetcher: func(ctx context.Context) ([]string, error) {
+				return nil, errors.New("fetch error")
+			},
+			err:     errors.New("fetch error"),
+			domains: client.DefaultDomains(),
+			known:   client.DefaultDomains(),
+		},
+		{
+			name:    "Refreshed domains",
+			refresh: true,
+			json:    true,
+			fetcher: func(ctx context.Context) ([]string, error) {
+				return []string{"yopmail.com", "new.yopmail.test"}, nil
+			},
+			output:   `{"domains":["yopmail.com","new.yopmail.test"]}` + "\n",
+			domains:  []string{"yopmail.com", "new.yopmail.test"},
+			known:    append(client.DefaultDomains(), "new.yopmail.test"),
+			inboxArg: "test@new.yopmail.test",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			sessionFile = filepath.Join(t.TempDir(), "session.json")
+			domainsRefresh = scenario.refresh
+			dumpJSON = scenario.json
+			defer func() {
+				sessionFile = ""
+				domainsRefresh = false
+				dumpJSON = false
+			}()
+
+			var output bytes.Buffer
+			cmd := &cobra.Command{}
+			cmd.SetContext(context.Background())
+			cmd.SetOut(&output)
+			err := domains(scenario.fetcher)(cmd, []string{})
+			assert.Equal(t, scenario.err, err)
+			assert.Equal(t, scenario.output, output.String())
+
+			path, err := domainsFilePath()
+			assert.NoError(t, err)
+			stored, err := client.LoadDomains(path, "")
+			assert.NoError(t, err)
+			assert.Equal(t, scenario.domains, stored)
+			assert.Equal(t, scenario.known, knownDomains())
+
+			if scenario.inboxArg != "" {
+				name, err := normalizeInboxName(scenario.inboxArg)
+				assert.NoError(t, err)
+				assert.Equal(t, "test", name)
+			}
+		})
+	}
+}
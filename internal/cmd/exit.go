@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/antham/yogo/v4/internal/address"
+	"github.com/antham/yogo/v4/internal/client"
+	"github.com/antham/yogo/v4/internal/inbox"
+)
+
+// Exit codes of the process, they are documented in the README
+// and must not change as scripts rely on them
+const (
+	exitOK          = 0
+	exitError       = 1
+	exitOffset      = 2
+	exitCAPTCHA     = 3
+	exitNetwork     = 4
+	exitHTTP        = 5
+	exitParse       = 6
+	exitToken       = 7
+	exitEmptyInbox  = 8
+	exitTimeout     = 9
+	exitDomain      = 10
+	exitSend        = 11
+	exitInterrupted = 130
+)
+
+// exitCode returns the exit code matching the class of an error
+func exitCode(err error) int {
+	var offsetErr *inbox.OffsetError
+	var networkErr *client.NetworkError
+	var HTTPErr *client.HTTPError
+	var parseErr *client.ParseError
+	var tokenErr *client.TokenError
+	var domainErr *address.DomainError
+	var sendErr *client.SendError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, context.Canceled):
+		return exitInterrupted
+	case errors.Is(err, client.ErrCaptcha):
+		return exitCAPTCHA
+	case errors.As(err, &tokenErr):
+		return exitToken
+	case errors.As(err, &parseErr):
+		return exitParse
+	case errors.As(err, &HTTPErr):
+		return exitHTTP
+	case errors.As(err, &networkErr):
+		return exitNetwork
+	case errors.Is(err, inbox.ErrEmptyInbox):
+		return exitEmptyInbox
+	case errors.As(err, &offsetErr):
+		return exitOffset
+	case errors.Is(err, errWaitTimeout):
+		return exitTimeout
+	case errors.As(err, &domainErr):
+		return exitDomain
+	case errors.As(err, &sendErr):
+		return exitSend
+	default:
+		return exitError
+	}
+}
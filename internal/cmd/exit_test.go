@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/antham/yogo/v4/internal/address"
+	"github.com/antham/yogo/v4/internal/client"
+	"github.com/antham/yogo/v4/internal/inbox"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCode(t *testing.T) {
+	type scenario struct {
+		name     string
+		err      error
+		expected int
+	}
+
+	scenarios := []scenario{
+		{"no error", nil, 0},
+		{"unknown error", errors.New("whatever"), 1},
+		{"offset error", &inbox.OffsetError{Offset: "a", Reason: "must be an integer"}, 2},
+		{"CAPTCHA", fmt.Errorf("failure : %w", client.ErrCaptcha), 3},
+		{"network error", fmt.Errorf("failure : %w", &client.NetworkError{URL: "https://yopmail.com", Err: errors.New("connection refused")}), 4},
+		{"HTTP error", fmt.Errorf("failure : %w", &client.HTTPError{URL: "https://yopmail.com", StatusCode: 404}), 5},
+		{"parse error", &client.ParseError{Page: "mail", Err: errors.New("mail content not found")}, 6},
+		{"token error", &client.TokenError{Token: "yp"}, 7},
+		{"empty inbox", inbox.ErrEmptyInbox, 8},
+		{"wait timeout", errWaitTimeout, 9},
+		{"foreign domain", domainError(&address.DomainError{Domain: "example.com"}), 10},
+		{"mail not sent", &client.SendError{URL: "https://yopmail.com/en/writepost"}, 11},
+		{"interrupted", &client.NetworkError{URL: "https://yopmail.com", Err: context.Canceled}, 130},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			assert.Equal(t, scenario.expected, exitCode(scenario.err))
+		})
+	}
+}
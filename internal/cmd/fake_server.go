@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/antham/yogo/v4/internal/fakeserver"
+	"github.com/spf13/cobra"
+)
+
+var fakeServerAddr string
+var fakeServerCAPTCHAAfter int
+
+var fakeServerCmd = &cobra.Command{
+	Use:   "fake-server",
+	Short: "Run a local yopmail emulator for offline testing",
+	Long: `Run a local yopmail emulator for offline testing.
+
+Point yogo to it with the --base-url flag or the YOGO_BASE_URL environment variable.
+
+The emulator exposes an admin API:
+  GET    /admin/inboxes/<inbox>/mails  list the mails of an inbox
+  POST   /admin/inboxes/<inbox>/mails  add a mail, e.g. {"from":"a@b.com","fromName":"A","subject":"Hi","body":"<p>Hello</p>"}
+  DELETE /admin/inboxes/<inbox>/mails  flush an inbox
+  PUT    /admin/captcha                enable the CAPTCHA, e.g. {"enabled":true} or {"after":10}
+  POST   /admin/tokens/rotate          expire the session tokens`,
+	Args: cobra.NoArgs,
+	RunE: fakeServer,
+}
+
+func fakeServer(cmd *cobra.Command, args []string) error {
+	l, err := net.Listen("tcp", fakeServerAddr)
+	if err != nil {
+		return err
+	}
+	server := fakeserver.New()
+	server.SetCAPTCHA(false, fakeServerCAPTCHAAfter)
+	srv := &http.Server{Handler: server.Handler()}
+	go func() {
+		<-cmd.Context().Done()
+		_ = srv.Shutdown(context.Background())
+	}()
+	cmd.Println(info(fmt.Sprintf("Fake yopmail server listening on http://%s", l.Addr())))
+	if err := srv.Serve(l); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func init() {
+	fakeServerCmd.Flags().StringVar(&fakeServerAddr, "addr", "127.0.0.1:8025", "Address to listen on")
+	fakeServerCmd.Flags().IntVar(&fakeServerCAPTCHAAfter, "captcha-after", 0, "Enable the CAPTCHA once this number of requests have been served, 0 never enables it")
+	RootCmd.AddCommand(fakeServerCmd)
+}
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeServer(t *testing.T) {
+	fakeServerAddr = "127.0.0.1:0"
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	var output bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetContext(ctx)
+	cmd.SetOut(&output)
+	assert.NoError(t, fakeServer(cmd, []string{}))
+	assert.Contains(t, output.String(), "Fake yopmail server listening on http://127.0.0.1:")
+}
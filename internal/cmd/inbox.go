@@ -1,12 +1,17 @@
 package cmd
 
 import (
+	"context"
+
 	"github.com/antham/yogo/v4/internal/client"
 	"github.com/antham/yogo/v4/internal/inbox"
 	"github.com/spf13/cobra"
 )
 
-type inboxBuilder func(string) (Inbox, error)
+type inboxBuilder func(context.Context, string) (Inbox, error)
+
+// inboxesBuilder returns a builder of inboxes sharing the same client
+type inboxesBuilder func(context.Context) (func(string) Inbox, error)
 
 var inboxCmd = &cobra.Command{
 	Use:   "inbox",
@@ -20,7 +25,25 @@ func init() {
 	RootCmd.AddCommand(inboxCmd)
 }
 
-func newInbox[M client.MailDoc](name string) (Inbox, error) {
-	in, err := inbox.NewInbox[M](name, enableDebugMode)
+func newInbox[M client.MailDoc](ctx context.Context, name string) (Inbox, error) {
+	config, err := clientConfig()
+	if err != nil {
+		return nil, err
+	}
+	in, err := inbox.NewInbox[M](ctx, name, config)
 	return Inbox(in), err
 }
+
+func newInboxes[M client.MailDoc](ctx context.Context) (func(string) Inbox, error) {
+	config, err := clientConfig()
+	if err != nil {
+		return nil, err
+	}
+	c, err := client.New[M](ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	return func(name string) Inbox {
+		return inbox.NewInboxWithClient(name, c)
+	}, nil
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/antham/yogo/v4/internal/client"
+	"github.com/antham/yogo/v4/internal/inbox"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var attachmentsSave = ""
+var attachmentsPart = 0
+
+var inboxAttachmentsCmd = &cobra.Command{
+	Use:   "attachments <inbox> <offset>",
+	Short: "List and download the attachments of an email",
+	Long: `List and download the attachments of an email.
+
+The attachments are listed with their number, filename, content type
+and size. Use --save to write them decoded in a directory, --part
+restricts the download to one of them. With --part alone, the
+attachment is written on stdout.`,
+	RunE: inboxAttachments(newInbox[client.MailSourceDoc]),
+	Args: cobra.ExactArgs(2),
+}
+
+func inboxAttachments(inboxBuilder inboxBuilder) cobraCmd {
+	return func(cmd *cobra.Command, args []string) error {
+		if attachmentsPart < 0 {
+			return errors.New("part must be greater than 0")
+		}
+		r, err := fetchMailAt(cmd.Context(), inboxBuilder, args[0], args[1])
+		if err != nil {
+			return err
+		}
+		mail, ok := r.(*inbox.SourceMail)
+		if !ok {
+			return errors.New("unexpected mail type")
+		}
+
+		attachments := mail.Attachments
+		if attachmentsPart > 0 {
+			if attachmentsPart > len(attachments) {
+				return fmt.Errorf("part %d doesn't exist, the email has %d attachments", attachmentsPart, len(attachments))
+			}
+			attachments = attachments[attachmentsPart-1 : attachmentsPart]
+		}
+		switch {
+		case attachmentsSave != "":
+			return saveAttachments(cmd, attachmentsSave, attachments)
+		case attachmentsPart > 0:
+			_, err := cmd.OutOrStdout().Write(attachments[0].Content)
+			return err
+		case dumpJSON:
+			b, err := json.Marshal(struct {
+				Attachments []inbox.Attachment `json:"attachments"`
+			}{attachments})
+			if err != nil {
+				return err
+			}
+			cmd.Println(string(b))
+		case len(attachments) == 0:
+			cmd.Println(info("The email has no attachment"))
+		default:
+			lines := []string{}
+			for _, a := range attachments {
+				lines = append(lines, fmt.Sprintf("%2d %s %s %s",
+					a.Index,
+					color.MagentaString(attachmentFilename(a)),
+					color.YellowString(a.ContentType),
+					color.GreenString(formatSize(a.Size)),
+				))
+			}
+			cmd.Println(strings.Join(lines, "\n"))
+		}
+		return nil
+	}
+}
+
+// saveAttachments writes attachments in a directory, the attachments
+// sharing the same name are prefixed with their number
+func saveAttachments(cmd *cobra.Command, dir string, attachments []inbox.Attachment) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	used := map[string]bool{}
+	for _, a := range attachments {
+		name := attachmentFilename(a)
+		if used[name] {
+			name = fmt.Sprintf("%d-%s", a.Index, name)
+		}
+		used[name] = true
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, a.Content, 0o644); err != nil {
+			return err
+		}
+		cmd.Println(success(fmt.Sprintf(`Attachment "%d" saved to %s`, a.Index, path)))
+	}
+	return nil
+}
+
+// attachmentFilename returns a filename safe to be written in a directory,
+// a name is made from the number and the content type when none is sent
+func attachmentFilename(a inbox.Attachment) string {
+	name := filepath.Base(filepath.FromSlash(strings.ReplaceAll(a.Filename, `\`, "/")))
+	if name != "." && name != ".." && name != string(filepath.Separator) {
+		return name
+	}
+	name = fmt.Sprintf("part-%d", a.Index)
+	if extensions, err := mime.ExtensionsByType(a.ContentType); err == nil && len(extensions) > 0 {
+		name += extensions[0]
+	}
+	return name
+}
+
+func formatSize(size int) string {
+	switch {
+	case size < 1024:
+		return fmt.Sprintf("%d B", size)
+	case size < 1024*1024:
+		return fmt.Sprintf("%.1f KB", float64(size)/1024)
+	default:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1024*1024))
+	}
+}
+
+func init() {
+	inboxAttachmentsCmd.Flags().StringVar(&attachmentsSave, "save", "", "Directory where the attachments are written")
+	inboxAttachmentsCmd.Flags().IntVar(&attachmentsPart, "part", 0, "Number of the attachment to download")
+	inboxCmd.AddCommand(inboxAttachmentsCmd)
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antham/yogo/v4/internal/inbox"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInboxAttachments(t *testing.T) {
+	type scenario struct {
+		name        string
+		args        []string
+		part        int
+		save        bool
+		json        bool
+		mail        inbox.Render
+		errExpected error
+		output      string
+		files       map[string]string
+	}
+
+	mail := &inbox.SourceMail{
+		ID: "abcdefg",
+		Attachments: []inbox.Attachment{
+			{Index: 1, Filename: "../invoice.pdf", ContentType: "application/pdf", Size: 9, Content: []byte("%PDF-1.4\n")},
+			{Index: 2, ContentType: "image/png", Size: 2048, Content: []byte("png")},
+			{Index: 3, Filename: "invoice.pdf", ContentType: "application/pdf", Size: 3, Content: []byte("pdf")},
+		},
+	}
+
+	scenarios := []scenario{
+		{
+			name:        "Unexpected mail type",
+			args:        []string{"test", "1"},
+			mail:        MailMock{},
+			errExpected: errors.New("unexpected mail type"),
+		},
+		{
+			name:        "Part out of range",
+			args:        []string{"test", "1"},
+			part:        4,
+			mail:        mail,
+			errExpected: errors.New("part 4 doesn't exist, the email has 3 attachments"),
+		},
+		{
+			name:   "No attachment",
+			args:   []string{"test", "1"},
+			mail:   &inbox.SourceMail{},
+			output: "The email has no attachment\n",
+		},
+		{
+			name: "List the attachments",
+			args: []string{"test", "1"},
+			mail: mail,
+			output: ` 1 invoice.pdf application/pdf 9 B
+ 2 part-2.png image/png 2.0 KB
+ 3 invoice.pdf application/pdf 3 B
+`,
+		},
+		{
+			name:   "List the attachments as JSON",
+			args:   []string{"test", "1"},
+			json:   true,
+			mail:   mail,
+			output: `{"attachments":[{"index":1,"filename":"../invoice.pdf","contentType":"application/pdf","size":9},{"index":2,"contentType":"image/png","size":2048},{"index":3,"filename":"invoice.pdf","contentType":"application/pdf","size":3}]}` + "\n",
+		},
+		{
+			name:   "Write an attachment on stdout",
+			args:   []string{"test", "1"},
+			part:   1,
+			mail:   mail,
+			output: "%PDF-1.4\n",
+		},
+		{
+			name: "Save the attachments",
+			args: []string{"test", "1"},
+			save: true,
+			mail: mail,
+			files: map[string]string{
+				"invoice.pdf":   "%PDF-1.4\n",
+				"part-2.png":    "png",
+				"3-invoice.pdf": "pdf",
+			},
+		},
+		{
+			name: "Save one attachment",
+			args: []string{"test", "1"},
+			part: 3,
+			save: true,
+			mail: mail,
+			files: map[string]string{
+				"invoice.pdf": "pdf",
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "attachments")
+			attachmentsPart = scenario.part
+			attachmentsSave = ""
+			if scenario.save {
+				attachmentsSave = dir
+			}
+			dumpJSON = scenario.json
+			defer func() {
+				attachmentsPart = 0
+				attachmentsSave = ""
+				dumpJSON = false
+			}()
+
+			var output bytes.Buffer
+			cmd := &cobra.Command{}
+			cmd.SetContext(context.Background())
+			cmd.SetOut(&output)
+			err := inboxAttachments(func(ctx context.Context, name string) (Inbox, error) {
+				mock := &InboxMock{fetchMail: scenario.mail}
+				mock.count = 1
+				mock.items = []inbox.InboxItem{{ID: "abcdefg"}}
+				return mock, nil
+			})(cmd, scenario.args)
+			assert.Equal(t, scenario.errExpected, err)
+			if scenario.files == nil {
+				assert.Equal(t, scenario.output, output.String())
+				assert.NoDirExists(t, dir)
+				return
+			}
+			entries, err := os.ReadDir(dir)
+			assert.NoError(t, err)
+			assert.Len(t, entries, len(scenario.files))
+			for name, content := range scenario.files {
+				b, err := os.ReadFile(filepath.Join(dir, name))
+				assert.NoError(t, err)
+				assert.Equal(t, content, string(b))
+				assert.Contains(t, output.String(), "saved to "+filepath.Join(dir, name))
+			}
+		})
+	}
+}
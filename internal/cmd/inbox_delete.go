@@ -16,22 +16,25 @@ var inboxDeleteCmd = &cobra.Command{
 
 func inboxDelete(inboxBuilder inboxBuilder) cobraCmd {
 	return func(cmd *cobra.Command, args []string) error {
-		identifier := normalizeInboxName(args[0])
+		identifier, err := normalizeInboxName(args[0])
+		if err != nil {
+			return err
+		}
 		offset, err := parseOffset(args[1])
 		if err != nil {
 			return err
 		}
-		in, err := inboxBuilder(identifier)
+		in, err := inboxBuilder(cmd.Context(), identifier)
 		if err != nil {
 			return err
 		}
-		if err := in.ParseInboxPages(offset); err != nil {
+		if err := in.ParseInboxPages(cmd.Context(), offset); err != nil {
 			return err
 		}
 		if err := checkOffset(in.Count(), offset); err != nil {
 			return err
 		}
-		if err := in.Delete(offset - 1); err != nil {
+		if err := in.Delete(cmd.Context(), offset-1); err != nil {
 			return err
 		}
 		cmd.Println(success(fmt.Sprintf(`Email "%d" successfully deleted`, offset)))
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"testing"
 
@@ -25,7 +26,7 @@ func TestInboxDelete(t *testing.T) {
 			name:        "No mails found",
 			args:        []string{"test", "1"},
 			errExpected: errors.New("inbox is empty"),
-			inboxBuilder: func(name string) (Inbox, error) {
+			inboxBuilder: func(ctx context.Context, name string) (Inbox, error) {
 				mock := &InboxMock{}
 				mock.items = []inbox.InboxItem{}
 				return mock, nil
@@ -34,8 +35,8 @@ func TestInboxDelete(t *testing.T) {
 		{
 			name:        "Failure when parsing offset",
 			args:        []string{"test", "-1"},
-			errExpected: errors.New(`offset "-1" must be greater than 0`),
-			inboxBuilder: func(name string) (Inbox, error) {
+			errExpected: &inbox.OffsetError{Offset: "-1", Reason: "must be greater than 0"},
+			inboxBuilder: func(ctx context.Context, name string) (Inbox, error) {
 				mock := &InboxMock{}
 				mock.items = []inbox.InboxItem{}
 				return mock, nil
@@ -45,7 +46,7 @@ func TestInboxDelete(t *testing.T) {
 			name:        "An error is thrown in inbox builder",
 			args:        []string{"test", "1"},
 			errExpected: errors.New("inbox builder error"),
-			inboxBuilder: func(name string) (Inbox, error) {
+			inboxBuilder: func(ctx context.Context, name string) (Inbox, error) {
 				mock := &InboxMock{}
 				return mock, errors.New("inbox builder error")
 			},
@@ -54,7 +55,7 @@ func TestInboxDelete(t *testing.T) {
 			name:        "An error is thrown in parse inbox pages",
 			args:        []string{"test", "1"},
 			errExpected: errors.New("inbox pages error"),
-			inboxBuilder: func(name string) (Inbox, error) {
+			inboxBuilder: func(ctx context.Context, name string) (Inbox, error) {
 				mock := &InboxMock{parseInboxPagesError: errors.New("inbox pages error")}
 				return mock, nil
 			},
@@ -63,7 +64,7 @@ func TestInboxDelete(t *testing.T) {
 			name:        "An error is thrown when deleting a message",
 			args:        []string{"test", "1"},
 			errExpected: errors.New("delete message error"),
-			inboxBuilder: func(name string) (Inbox, error) {
+			inboxBuilder: func(ctx context.Context, name string) (Inbox, error) {
 				mock := &InboxMock{deleteError: errors.New("delete message error")}
 				mock.count = 1
 				mock.items = []inbox.InboxItem{
@@ -83,7 +84,7 @@ func TestInboxDelete(t *testing.T) {
 		{
 			name: "Email deleted successfully",
 			args: []string{"test", "1"},
-			inboxBuilder: func(name string) (Inbox, error) {
+			inboxBuilder: func(ctx context.Context, name string) (Inbox, error) {
 				mock := &InboxMock{}
 				mock.count = 1
 				mock.items = []inbox.InboxItem{
@@ -111,6 +112,7 @@ func TestInboxDelete(t *testing.T) {
 			var output bytes.Buffer
 			var outputErr bytes.Buffer
 			cmd := &cobra.Command{}
+			cmd.SetContext(context.Background())
 			cmd.SetOut(&output)
 			cmd.SetErr(&outputErr)
 			err := inboxDelete(scenario.inboxBuilder)(cmd, scenario.args)
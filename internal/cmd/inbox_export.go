@@ -0,0 +1,422 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	gomail "net/mail"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antham/yogo/v4/internal/client"
+	"github.com/antham/yogo/v4/internal/inbox"
+	"github.com/spf13/cobra"
+)
+
+var exportFormat = "mbox"
+var exportDest = ""
+var exportLimit = 0
+var exportInterval = time.Second
+
+var inboxExportCmd = &cobra.Command{
+	Use:   "export <inbox>",
+	Short: "Export all emails of an inbox to an mbox file or a Maildir",
+	Long: `Export all emails of an inbox to an mbox file or a Maildir.
+
+The source of every email is fetched, the oldest first, and written to
+--dest: an mbox file (mboxrd) the emails are appended to or a Maildir
+whose new folder receives them. --limit exports the most recent emails
+only.
+
+The identifiers of the exported emails are recorded in a file named
+after --dest with a .progress extension, an interrupted export is
+resumed by running the same command again and the emails already
+exported are skipped. The progress file is ignored when --dest no
+longer exists.
+
+The requests are paced by --rate-limit and the export waits --interval
+between two emails to not trigger a CAPTCHA.`,
+	RunE: inboxExport(newInbox[client.MailSourceDoc]),
+	Args: cobra.ExactArgs(1),
+}
+
+func inboxExport(inboxBuilder inboxBuilder) cobraCmd {
+	return func(cmd *cobra.Command, args []string) error {
+		identifier, err := normalizeInboxName(args[0])
+		if err != nil {
+			return err
+		}
+		if exportDest == "" {
+			return errors.New("--dest must be provided")
+		}
+		if exportLimit < 0 {
+			return errors.New("--limit must be greater than or equal to 0")
+		}
+		if exportInterval < 0 {
+			return errors.New("--interval must be greater than or equal to 0")
+		}
+		_, err = os.Stat(exportDest)
+		archiveFound := err == nil
+		archive, err := newArchive(exportFormat, exportDest)
+		if err != nil {
+			return err
+		}
+		defer archive.Close()
+		progressPath := filepath.Clean(exportDest) + ".progress"
+		progress, err := openExportProgress(progressPath)
+		if err != nil {
+			return err
+		}
+		defer progress.Close()
+		if !archiveFound && len(progress.exported) > 0 {
+			cmd.Println(fmt.Sprintf(`%s not found, the emails recorded in %s are exported again`, exportDest, progressPath))
+			if err := progress.reset(); err != nil {
+				return err
+			}
+		}
+		if err := progress.restore(archive); err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		in, err := inboxBuilder(ctx, identifier)
+		if err != nil {
+			return err
+		}
+		// the parsing stops at the number of mails of the inbox
+		limit := exportLimit
+		if limit == 0 {
+			limit = math.MaxInt32
+		}
+		if err := in.ParseInboxPages(ctx, limit); err != nil {
+			return err
+		}
+		if in.Count() == 0 {
+			return inbox.ErrEmptyInbox
+		}
+
+		exported, skipped := 0, 0
+		mails := in.GetMails()
+		// the inbox lists the most recent mails first
+		for offset := len(mails) - 1; offset >= 0; offset-- {
+			if progress.done(mails[offset].ID) {
+				skipped++
+				continue
+			}
+			if exported > 0 {
+				if err := sleep(ctx, exportInterval); err != nil {
+					return err
+				}
+			}
+			mail, err := in.Fetch(ctx, offset)
+			if err != nil {
+				return err
+			}
+			m, ok := mail.(*inbox.SourceMail)
+			if !ok {
+				return errors.New("unexpected mail type")
+			}
+			mark, err := archive.mark(m)
+			if err != nil {
+				return err
+			}
+			if err := progress.start(m.ID, mark); err != nil {
+				return err
+			}
+			if err := archive.write(m, mark); err != nil {
+				return err
+			}
+			if err := progress.add(m.ID); err != nil {
+				return err
+			}
+			exported++
+			cmd.Println(fmt.Sprintf(`Email "%s" exported (%d/%d)`, m.ID, exported+skipped, len(mails)))
+		}
+
+		output := fmt.Sprintf(`%d emails of inbox "%s" exported to %s`, exported, args[0], exportDest)
+		if skipped > 0 {
+			output += fmt.Sprintf(", %d already exported", skipped)
+		}
+		cmd.Println(success(output))
+		return nil
+	}
+}
+
+// sleep waits for a delay unless the context is done
+func sleep(ctx context.Context, delay time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(delay):
+		return nil
+	}
+}
+
+// archive stores the exported mails
+type archive interface {
+	// mark returns where a mail is written in the archive
+	mark(*inbox.SourceMail) (string, error)
+	// write stores a mail where its mark tells
+	write(m *inbox.SourceMail, mark string) error
+	// restore tells if the mail recorded with a mark is in the
+	// archive, a mail partially written is removed from it
+	restore(mark string, written bool) (bool, error)
+	Close() error
+}
+
+func newArchive(format string, dest string) (archive, error) {
+	switch format {
+	case "mbox":
+		f, err := os.OpenFile(dest, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, err
+		}
+		return &mbox{file: f}, nil
+	case "maildir":
+		for _, dir := range []string{"tmp", "new", "cur"} {
+			if err := os.MkdirAll(filepath.Join(dest, dir), 0o755); err != nil {
+				return nil, err
+			}
+		}
+		return &maildir{dir: dest}, nil
+	}
+	return nil, fmt.Errorf(`--format must be "mbox" or "maildir", got "%s"`, format)
+}
+
+// mbox appends the mails to a file using the mboxrd format, the
+// lines of a mail starting with "From " are quoted with ">"
+type mbox struct {
+	file *os.File
+}
+
+// mark returns the size of the file, the offset of the next mail
+func (b *mbox) mark(m *inbox.SourceMail) (string, error) {
+	info, err := b.file.Stat()
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(info.Size(), 10), nil
+}
+
+func (b *mbox) write(m *inbox.SourceMail, mark string) error {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "From %s %s\n", envelopeSender(m), mailDate(m).UTC().Format(time.ANSIC))
+	for line := range strings.Lines(unixLines(m.EML())) {
+		if strings.HasPrefix(strings.TrimLeft(line, ">"), "From ") {
+			buf.WriteString(">")
+		}
+		buf.WriteString(line)
+	}
+	buf.WriteString("\n")
+	if _, err := b.file.WriteString(buf.String()); err != nil {
+		return err
+	}
+	return b.file.Sync()
+}
+
+// restore truncates the file at the offset of a mail whose write was
+// interrupted, the mail may be incomplete
+func (b *mbox) restore(mark string, written bool) (bool, error) {
+	offset, err := strconv.ParseInt(mark, 10, 64)
+	if err != nil {
+		return false, fmt.Errorf(`invalid offset "%s" in the progress file`, mark)
+	}
+	info, err := b.file.Stat()
+	if err != nil {
+		return false, err
+	}
+	if info.Size() < offset {
+		return false, errors.New("the mbox file is shorter than recorded in the progress file, remove the progress file to export all emails again")
+	}
+	if written {
+		return true, nil
+	}
+	return false, b.file.Truncate(offset)
+}
+
+func (b *mbox) Close() error {
+	return b.file.Close()
+}
+
+// maildir delivers the mails in the new folder of a Maildir,
+// a mail is written in the tmp folder first then moved
+type maildir struct {
+	dir string
+}
+
+// mark returns the name of the file of a mail
+func (d *maildir) mark(m *inbox.SourceMail) (string, error) {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "localhost"
+	}
+	sanitizer := strings.NewReplacer("/", "_", ":", "_", ".", "_")
+	return fmt.Sprintf("%d.%s.%s", mailDate(m).Unix(), sanitizer.Replace(m.ID), sanitizer.Replace(host)), nil
+}
+
+func (d *maildir) write(m *inbox.SourceMail, name string) error {
+	tmp := filepath.Join(d.dir, "tmp", name)
+	if err := os.WriteFile(tmp, []byte(unixLines(m.EML())), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(d.dir, "new", name))
+}
+
+// restore removes the temporary file of a mail whose write was
+// interrupted, the mail is written once moved in the new folder,
+// a mail client moves it to the cur folder with flags appended
+func (d *maildir) restore(mark string, written bool) (bool, error) {
+	if written {
+		return true, nil
+	}
+	if err := os.Remove(filepath.Join(d.dir, "tmp", mark)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return false, err
+	}
+	for _, dir := range []string{"new", "cur"} {
+		matches, err := filepath.Glob(filepath.Join(d.dir, dir, mark) + "*")
+		if err != nil {
+			return false, err
+		}
+		if len(matches) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (d *maildir) Close() error {
+	return nil
+}
+
+// envelopeSender returns the address of the sender of a mail
+func envelopeSender(m *inbox.SourceMail) string {
+	if from := m.Addresses["From"]; len(from) > 0 {
+		return from[0].Mail
+	}
+	return "MAILER-DAEMON"
+}
+
+// mailDate returns the date of a mail, the current
+// date is used when its Date header can't be parsed
+func mailDate(m *inbox.SourceMail) time.Time {
+	if dates := m.Headers["Date"]; len(dates) > 0 {
+		if date, err := gomail.ParseDate(dates[0]); err == nil {
+			return date
+		}
+	}
+	return time.Now()
+}
+
+// unixLines turns the CRLF line endings of a message into LF,
+// the line ending used by mbox files and Maildirs, the last
+// line is ended as well
+func unixLines(message []byte) string {
+	s := strings.ReplaceAll(string(message), "\r\n", "\n")
+	if !strings.HasSuffix(s, "\n") {
+		s += "\n"
+	}
+	return s
+}
+
+// exportProgress records the identifiers of the exported mails, a
+// mail is recorded with its mark in the archive before it is written,
+// then its identifier alone once written, so a mail whose write was
+// interrupted can be removed from the archive
+type exportProgress struct {
+	file     *os.File
+	exported map[string]bool
+	last     *exportMark
+}
+
+// exportMark is a mail recorded before being written
+type exportMark struct {
+	ID   string
+	mark string
+}
+
+func openExportProgress(path string) (*exportProgress, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	p := &exportProgress{file: f, exported: map[string]bool{}}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		ID, mark, found := strings.Cut(strings.TrimSpace(scanner.Text()), "\t")
+		switch {
+		case ID == "":
+		case found:
+			p.last = &exportMark{ID: ID, mark: mark}
+		default:
+			p.exported[ID] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *exportProgress) done(ID string) bool {
+	return p.exported[ID]
+}
+
+// start records a mail before it is written
+func (p *exportProgress) start(ID string, mark string) error {
+	if _, err := fmt.Fprintf(p.file, "%s\t%s\n", ID, mark); err != nil {
+		return err
+	}
+	p.last = &exportMark{ID: ID, mark: mark}
+	return p.file.Sync()
+}
+
+// add records a mail once written
+func (p *exportProgress) add(ID string) error {
+	if _, err := fmt.Fprintln(p.file, ID); err != nil {
+		return err
+	}
+	p.exported[ID] = true
+	return p.file.Sync()
+}
+
+// restore checks the archive against the last recorded mail,
+// it is recorded as written when its write completed
+func (p *exportProgress) restore(a archive) error {
+	if p.last == nil {
+		return nil
+	}
+	written, err := a.restore(p.last.mark, p.exported[p.last.ID])
+	if err != nil || !written || p.exported[p.last.ID] {
+		return err
+	}
+	return p.add(p.last.ID)
+}
+
+// reset forgets all the recorded mails
+func (p *exportProgress) reset() error {
+	if err := p.file.Truncate(0); err != nil {
+		return err
+	}
+	p.exported = map[string]bool{}
+	p.last = nil
+	return nil
+}
+
+func (p *exportProgress) Close() error {
+	return p.file.Close()
+}
+
+func init() {
+	inboxExportCmd.Flags().StringVar(&exportFormat, "format", exportFormat, `Format of the export, "mbox" or "maildir"`)
+	inboxExportCmd.Flags().StringVar(&exportDest, "dest", "", "Path of the mbox file or of the Maildir")
+	inboxExportCmd.Flags().IntVar(&exportLimit, "limit", 0, "Export the most recent emails only, 0 exports all emails")
+	inboxExportCmd.Flags().DurationVar(&exportInterval, "interval", exportInterval, "Delay between the export of two emails")
+	inboxCmd.AddCommand(inboxExportCmd)
+}
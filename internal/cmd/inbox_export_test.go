@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/antham/yogo/v4/internal/inbox"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+// exportInboxMock returns the sources of the listed items
+type exportInboxMock struct {
+	InboxMock
+	mails   map[string]*inbox.SourceMail
+	fetched []int
+}
+
+func (i *exportInboxMock) Fetch(ctx context.Context, offset int) (inbox.Render, error) {
+	i.fetched = append(i.fetched, offset)
+	if i.fetchError != nil {
+		return nil, i.fetchError
+	}
+	return i.mails[i.items[offset].ID], nil
+}
+
+func TestInboxExport(t *testing.T) {
+	type scenario struct {
+		name     string
+		args     []string
+		format   string
+		noDest   bool
+		progress string
+		setup    func(t *testing.T, dir string)
+		mock     *exportInboxMock
+		err      error
+		fetched  []int
+		files    map[string]string
+		output   string
+	}
+
+	mails := map[string]*inbox.SourceMail{
+		"a": {
+			ID:        "a",
+			Headers:   map[string][]string{"Date": {"Mon, 02 Jan 2006 15:04:05 +0000"}},
+			Addresses: map[string][]inbox.Address{"From": {{Name: "Shop", Mail: "shop@example.com"}}},
+			Message:   "Date: Mon, 02 Jan 2006 15:04:05 +0000\r\nFrom: Shop <shop@example.com>\r\n\r\nHello\r\nFrom here\r\n>From there",
+		},
+		"b": {
+			ID:      "b",
+			Headers: map[string][]string{"Date": {"Tue, 03 Jan 2006 15:04:05 +0000"}},
+			Message: "Date: Tue, 03 Jan 2006 15:04:05 +0000\n\nBye",
+		},
+	}
+	items := []inbox.InboxItem{{ID: "b"}, {ID: "a"}}
+	mboxA := `From shop@example.com Mon Jan  2 15:04:05 2006
+Date: Mon, 02 Jan 2006 15:04:05 +0000
+From: Shop <shop@example.com>
+
+Hello
+>From here
+>>From there
+
+`
+	mboxB := `From MAILER-DAEMON Tue Jan  3 15:04:05 2006
+Date: Tue, 03 Jan 2006 15:04:05 +0000
+
+Bye
+
+`
+	maildirA, err := (&maildir{}).mark(mails["a"])
+	assert.NoError(t, err)
+	maildirB, err := (&maildir{}).mark(mails["b"])
+	assert.NoError(t, err)
+	writeArchive := func(content string) func(t *testing.T, dir string) {
+		return func(t *testing.T, dir string) {
+			assert.NoError(t, os.WriteFile(filepath.Join(dir, "archive"), []byte(content), 0o644))
+		}
+	}
+
+	scenarios := []scenario{
+		{
+			name:   "Missing destination",
+			args:   []string{"test"},
+			noDest: true,
+			mock:   &exportInboxMock{},
+			err:    errors.New("--dest must be provided"),
+		},
+		{
+			name:   "Unknown format",
+			args:   []string{"test"},
+			format: "pst",
+			mock:   &exportInboxMock{},
+			err:    errors.New(`--format must be "mbox" or "maildir", got "pst"`),
+		},
+		{
+			name: "An error is thrown in parse inbox pages",
+			args: []string{"test"},
+			mock: &exportInboxMock{InboxMock: InboxMock{parseInboxPagesError: errors.New("inbox pages error")}},
+			err:  errors.New("inbox pages error"),
+		},
+		{
+			name: "Empty inbox",
+			args: []string{"test"},
+			mock: &exportInboxMock{},
+			err:  inbox.ErrEmptyInbox,
+		},
+		{
+			name:    "An error is thrown in fetch",
+			args:    []string{"test"},
+			mock:    &exportInboxMock{InboxMock: InboxMock{count: 2, items: items, fetchError: errors.New("fetch error")}},
+			err:     errors.New("fetch error"),
+			fetched: []int{1},
+			files:   map[string]string{"archive": ""},
+		},
+		{
+			name:    "Export to an mbox file",
+			args:    []string{"test"},
+			mock:    &exportInboxMock{InboxMock: InboxMock{count: 2, items: items}, mails: mails},
+			fetched: []int{1, 0},
+			files: map[string]string{
+				"archive":          mboxA + mboxB,
+				"archive.progress": "a\t0\na\nb\t147\nb\n",
+			},
+			output: `Email "a" exported (1/2)
+Email "b" exported (2/2)
+2 emails of inbox "test" exported to `,
+		},
+		{
+			name:    "Export to a Maildir",
+			args:    []string{"test"},
+			format:  "maildir",
+			mock:    &exportInboxMock{InboxMock: InboxMock{count: 2, items: items}, mails: mails},
+			fetched: []int{1, 0},
+			files: map[string]string{
+				"archive/new/1136214245.a.": "Date: Mon, 02 Jan 2006 15:04:05 +0000\nFrom: Shop <shop@example.com>\n\nHello\nFrom here\n>From there\n",
+				"archive/new/1136300645.b.": "Date: Tue, 03 Jan 2006 15:04:05 +0000\n\nBye\n",
+				"archive.progress":          "a\t" + maildirA + "\na\nb\t" + maildirB + "\nb\n",
+			},
+		},
+		{
+			name:     "Resume an export",
+			args:     []string{"test"},
+			progress: "a\t0\na\n",
+			setup:    writeArchive(mboxA),
+			mock:     &exportInboxMock{InboxMock: InboxMock{count: 2, items: items}, mails: mails},
+			fetched:  []int{0},
+			files: map[string]string{
+				"archive":          mboxA + mboxB,
+				"archive.progress": "a\t0\na\nb\t147\nb\n",
+			},
+			output: `Email "b" exported (2/2)
+1 emails of inbox "test" exported to `,
+		},
+		{
+			name:     "Resume an export whose archive was removed",
+			args:     []string{"test"},
+			progress: "a\t0\na\n",
+			mock:     &exportInboxMock{InboxMock: InboxMock{count: 2, items: items}, mails: mails},
+			fetched:  []int{1, 0},
+			files: map[string]string{
+				"archive":          mboxA + mboxB,
+				"archive.progress": "a\t0\na\nb\t147\nb\n",
+			},
+			output: `are exported again
+Email "a" exported (1/2)
+Email "b" exported (2/2)
+2 emails of inbox "test" exported to `,
+		},
+		{
+			name:     "Resume an export interrupted while writing to an mbox file",
+			args:     []string{"test"},
+			progress: "a\t0\na\nb\t147\n",
+			setup:    writeArchive(mboxA + mboxB[:20]),
+			mock:     &exportInboxMock{InboxMock: InboxMock{count: 2, items: items}, mails: mails},
+			fetched:  []int{0},
+			files: map[string]string{
+				"archive":          mboxA + mboxB,
+				"archive.progress": "a\t0\na\nb\t147\nb\t147\nb\n",
+			},
+		},
+		{
+			name:     "Resume an export interrupted once a mail is delivered to a Maildir",
+			args:     []string{"test"},
+			format:   "maildir",
+			progress: "a\t" + maildirA + "\n",
+			setup: func(t *testing.T, dir string) {
+				assert.NoError(t, os.MkdirAll(filepath.Join(dir, "archive", "cur"), 0o755))
+				assert.NoError(t, os.WriteFile(filepath.Join(dir, "archive", "cur", maildirA+":2,S"), []byte("a"), 0o644))
+			},
+			mock:    &exportInboxMock{InboxMock: InboxMock{count: 2, items: items}, mails: mails},
+			fetched: []int{0},
+			files: map[string]string{
+				"archive/new/1136300645.b.": "Date: Tue, 03 Jan 2006 15:04:05 +0000\n\nBye\n",
+				"archive.progress":          "a\t" + maildirA + "\na\nb\t" + maildirB + "\nb\n",
+			},
+		},
+		{
+			name:     "Archive shorter than recorded",
+			args:     []string{"test"},
+			progress: "a\t0\na\nb\t147\nb\n",
+			setup:    writeArchive(mboxA[:20]),
+			mock:     &exportInboxMock{InboxMock: InboxMock{count: 2, items: items}, mails: mails},
+			err:      errors.New("the mbox file is shorter than recorded in the progress file, remove the progress file to export all emails again"),
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			dir := t.TempDir()
+			exportFormat = "mbox"
+			if scenario.format != "" {
+				exportFormat = scenario.format
+			}
+			exportDest = filepath.Join(dir, "archive")
+			if scenario.noDest {
+				exportDest = ""
+			}
+			exportInterval = 0
+			defer func() {
+				exportFormat = "mbox"
+				exportDest = ""
+				exportInterval = time.Second
+			}()
+			if scenario.setup != nil {
+				scenario.setup(t, dir)
+			}
+			if scenario.progress != "" {
+				assert.NoError(t, os.WriteFile(filepath.Join(dir, "archive.progress"), []byte(scenario.progress), 0o644))
+			}
+
+			var output bytes.Buffer
+			cmd := &cobra.Command{}
+			cmd.SetContext(context.Background())
+			cmd.SetOut(&output)
+			err := inboxExport(func(ctx context.Context, name string) (Inbox, error) {
+				return scenario.mock, nil
+			})(cmd, scenario.args)
+			assert.Equal(t, scenario.err, err)
+			assert.Equal(t, scenario.fetched, scenario.mock.fetched)
+			assert.Contains(t, output.String(), scenario.output)
+			for name, content := range scenario.files {
+				matches, err := filepath.Glob(filepath.Join(dir, name) + "*")
+				assert.NoError(t, err)
+				if !assert.NotEmpty(t, matches) {
+					continue
+				}
+				b, err := os.ReadFile(matches[0])
+				assert.NoError(t, err)
+				assert.Equal(t, content, string(b))
+			}
+		})
+	}
+}
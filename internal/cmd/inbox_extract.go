@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/antham/yogo/v4/internal/client"
+	"github.com/antham/yogo/v4/internal/inbox"
+	"github.com/spf13/cobra"
+)
+
+var extractLinks = false
+var extractCode = false
+var extractRegexp = ""
+var extractSource = false
+
+var inboxExtractCmd = &cobra.Command{
+	Use:   "extract <inbox> <offset>",
+	Short: "Extract links, verification codes or any value from an email",
+	Long: `Extract links, verification codes or any value from an email.
+
+The extracted values are written on stdout, one per line, without
+duplicates and in the order they appear in the email:
+
+  --links   the links of the email
+  --code    the numbers of 4 to 8 digits, like verification codes
+  --regex   the matches of a regular expression in the text and the
+            links of the email, the first group is printed instead
+            of the whole match when the expression defines one
+
+The values are extracted from the HTML version of the email, use
+--source to extract them from the text and HTML parts of its source.`,
+	RunE: inboxExtract(newInbox[client.MailHTMLDoc], newInbox[client.MailSourceDoc]),
+	Args: cobra.ExactArgs(2),
+}
+
+func inboxExtract(HTMLInboxBuilder inboxBuilder, sourceInboxBuilder inboxBuilder) cobraCmd {
+	return func(cmd *cobra.Command, args []string) error {
+		extract, err := extractor(extractLinks, extractCode, extractRegexp)
+		if err != nil {
+			return err
+		}
+		inboxBuilder := HTMLInboxBuilder
+		if extractSource {
+			inboxBuilder = sourceInboxBuilder
+		}
+		mail, err := fetchMailAt(cmd.Context(), inboxBuilder, args[0], args[1])
+		if err != nil {
+			return err
+		}
+		m, ok := mail.(inbox.Extractable)
+		if !ok {
+			return errors.New("no value can be extracted from this email")
+		}
+
+		values := extract(m)
+		if len(values) == 0 {
+			return errors.New("nothing was extracted from the email")
+		}
+		output := strings.Join(values, "\n")
+		if dumpJSON {
+			b, err := json.Marshal(struct {
+				Values []string `json:"values"`
+			}{values})
+			if err != nil {
+				return err
+			}
+			output = string(b)
+		}
+		// stdout is used rather than Println to pipe the values in scripts
+		_, err = fmt.Fprintln(cmd.OutOrStdout(), output)
+		return err
+	}
+}
+
+// extractor returns the extraction selected by the flags,
+// exactly one of them must be provided
+func extractor(links bool, code bool, expression string) (func(inbox.Extractable) []string, error) {
+	selected := 0
+	for _, b := range []bool{links, code, expression != ""} {
+		if b {
+			selected++
+		}
+	}
+	if selected != 1 {
+		return nil, errors.New("exactly one of --links, --code or --regex must be provided")
+	}
+	switch {
+	case links:
+		return inbox.ExtractLinks, nil
+	case code:
+		return inbox.ExtractCodes, nil
+	}
+	re, err := regexp.Compile(expression)
+	if err != nil {
+		return nil, fmt.Errorf("--regex is not a valid regular expression : %w", err)
+	}
+	return func(m inbox.Extractable) []string {
+		return inbox.ExtractMatches(m, re)
+	}, nil
+}
+
+func init() {
+	inboxExtractCmd.Flags().BoolVar(&extractLinks, "links", false, "Extract the links")
+	inboxExtractCmd.Flags().BoolVar(&extractCode, "code", false, "Extract the verification codes")
+	inboxExtractCmd.Flags().StringVar(&extractRegexp, "regex", "", "Extract the matches of a regular expression")
+	inboxExtractCmd.Flags().BoolVar(&extractSource, "source", false, "Extract the values from the source of the email")
+	inboxCmd.AddCommand(inboxExtractCmd)
+}
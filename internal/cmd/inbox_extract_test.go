@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/antham/yogo/v4/internal/inbox"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInboxExtract(t *testing.T) {
+	type scenario struct {
+		name        string
+		args        []string
+		links       bool
+		code        bool
+		regex       string
+		source      bool
+		json        bool
+		mail        inbox.Render
+		errExpected error
+		output      string
+	}
+
+	HTMLMail := &inbox.HTMLMail{
+		ID:    "abcdefg",
+		Body:  "Your code is 482913.\n\nConfirm ( https://example.com/confirm?token=abc ) or ignore this mail sent on 2024-03-01.",
+		Links: []string{"https://example.com/confirm?token=abc", "https://example.com/unsubscribe"},
+	}
+	sourceMail := &inbox.SourceMail{
+		ID:      "abcdefg",
+		Headers: map[string][]string{"Content-Type": {"text/html; charset=utf-8"}, "Content-Transfer-Encoding": {"quoted-printable"}},
+		Body:    `<p>Code: <b>123456</b></p><a href=3D"https://example.com/reset?id=3D42">Reset</a>`,
+	}
+
+	scenarios := []scenario{
+		{
+			name:        "No extraction selected",
+			args:        []string{"test", "1"},
+			errExpected: errors.New("exactly one of --links, --code or --regex must be provided"),
+		},
+		{
+			name:        "Several extractions selected",
+			args:        []string{"test", "1"},
+			links:       true,
+			code:        true,
+			errExpected: errors.New("exactly one of --links, --code or --regex must be provided"),
+		},
+		{
+			name:        "Invalid regular expression",
+			args:        []string{"test", "1"},
+			regex:       "(",
+			errExpected: errors.New("--regex is not a valid regular expression : error parsing regexp: missing closing ): `(`"),
+		},
+		{
+			name:        "Mail without extractable content",
+			args:        []string{"test", "1"},
+			links:       true,
+			mail:        MailMock{},
+			errExpected: errors.New("no value can be extracted from this email"),
+		},
+		{
+			name:        "Nothing extracted",
+			args:        []string{"test", "1"},
+			regex:       "nothing",
+			mail:        HTMLMail,
+			errExpected: errors.New("nothing was extracted from the email"),
+		},
+		{
+			name:  "Links of the HTML mail",
+			args:  []string{"test", "1"},
+			links: true,
+			mail:  HTMLMail,
+			output: `https://example.com/confirm?token=abc
+https://example.com/unsubscribe
+`,
+		},
+		{
+			name:   "Codes of the HTML mail as JSON",
+			args:   []string{"test", "1"},
+			code:   true,
+			json:   true,
+			mail:   HTMLMail,
+			output: `{"values":["482913"]}` + "\n",
+		},
+		{
+			name:   "First group of a regular expression",
+			args:   []string{"test", "1"},
+			regex:  `token=(\w+)`,
+			mail:   HTMLMail,
+			output: "abc\n",
+		},
+		{
+			name:   "Links of the source mail",
+			args:   []string{"test", "1"},
+			links:  true,
+			source: true,
+			mail:   sourceMail,
+			output: "https://example.com/reset?id=42\n",
+		},
+		{
+			name:   "Codes of the source mail",
+			args:   []string{"test", "1"},
+			code:   true,
+			source: true,
+			mail:   sourceMail,
+			output: "123456\n",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			extractLinks = scenario.links
+			extractCode = scenario.code
+			extractRegexp = scenario.regex
+			extractSource = scenario.source
+			dumpJSON = scenario.json
+			defer func() {
+				extractLinks = false
+				extractCode = false
+				extractRegexp = ""
+				extractSource = false
+				dumpJSON = false
+			}()
+
+			builder := func(source bool) inboxBuilder {
+				return func(ctx context.Context, name string) (Inbox, error) {
+					if source != scenario.source {
+						return nil, errors.New("wrong inbox kind")
+					}
+					mock := &InboxMock{fetchMail: scenario.mail}
+					mock.count = 1
+					mock.items = []inbox.InboxItem{{ID: "abcdefg"}}
+					return mock, nil
+				}
+			}
+			var output bytes.Buffer
+			cmd := &cobra.Command{}
+			cmd.SetContext(context.Background())
+			cmd.SetOut(&output)
+			err := inboxExtract(builder(false), builder(true))(cmd, scenario.args)
+			if scenario.errExpected != nil {
+				assert.EqualError(t, err, scenario.errExpected.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, scenario.output, output.String())
+		})
+	}
+}
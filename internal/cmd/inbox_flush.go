@@ -16,15 +16,18 @@ var inboxFlushCmd = &cobra.Command{
 
 func inboxFlush(inboxBuilder inboxBuilder) cobraCmd {
 	return func(cmd *cobra.Command, args []string) error {
-		identifier := normalizeInboxName(args[0])
-		in, err := inboxBuilder(identifier)
+		identifier, err := normalizeInboxName(args[0])
 		if err != nil {
 			return err
 		}
-		if err = in.ParseInboxPages(1); err != nil {
+		in, err := inboxBuilder(cmd.Context(), identifier)
+		if err != nil {
+			return err
+		}
+		if err = in.ParseInboxPages(cmd.Context(), 1); err != nil {
 			return err
 		}
-		if err := in.Flush(); err != nil {
+		if err := in.Flush(cmd.Context()); err != nil {
 			return err
 		}
 		cmd.Println(success(fmt.Sprintf(`Inbox "%s" successfully flushed`, args[0])))
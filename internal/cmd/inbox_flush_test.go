@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"testing"
 
@@ -24,7 +25,7 @@ func TestInboxFlush(t *testing.T) {
 		{
 			name: "No mails found",
 			args: []string{"test", "1"},
-			inboxBuilder: func(name string) (Inbox, error) {
+			inboxBuilder: func(ctx context.Context, name string) (Inbox, error) {
 				mock := &InboxMock{}
 				mock.items = []inbox.InboxItem{}
 				return mock, nil
@@ -36,7 +37,7 @@ func TestInboxFlush(t *testing.T) {
 			name:        "An error is thrown in inbox builder",
 			args:        []string{"test", "1"},
 			errExpected: errors.New("inbox builder error"),
-			inboxBuilder: func(name string) (Inbox, error) {
+			inboxBuilder: func(ctx context.Context, name string) (Inbox, error) {
 				mock := &InboxMock{}
 				return mock, errors.New("inbox builder error")
 			},
@@ -45,7 +46,7 @@ func TestInboxFlush(t *testing.T) {
 			name:        "An error is thrown in parse inbox pages",
 			args:        []string{"test", "1"},
 			errExpected: errors.New("inbox pages error"),
-			inboxBuilder: func(name string) (Inbox, error) {
+			inboxBuilder: func(ctx context.Context, name string) (Inbox, error) {
 				mock := &InboxMock{parseInboxPagesError: errors.New("inbox pages error")}
 				return mock, nil
 			},
@@ -54,7 +55,7 @@ func TestInboxFlush(t *testing.T) {
 			name:        "An error is thrown when flushing inbox",
 			args:        []string{"test", "1"},
 			errExpected: errors.New("flush inbox error"),
-			inboxBuilder: func(name string) (Inbox, error) {
+			inboxBuilder: func(ctx context.Context, name string) (Inbox, error) {
 				mock := &InboxMock{flushError: errors.New("flush inbox error")}
 				mock.count = 1
 				mock.items = []inbox.InboxItem{
@@ -74,7 +75,7 @@ func TestInboxFlush(t *testing.T) {
 		{
 			name: "Inbox flushed successfully",
 			args: []string{"test", "1"},
-			inboxBuilder: func(name string) (Inbox, error) {
+			inboxBuilder: func(ctx context.Context, name string) (Inbox, error) {
 				mock := &InboxMock{}
 				mock.count = 1
 				mock.items = []inbox.InboxItem{
@@ -102,6 +103,7 @@ func TestInboxFlush(t *testing.T) {
 			var output bytes.Buffer
 			var outputErr bytes.Buffer
 			cmd := &cobra.Command{}
+			cmd.SetContext(context.Background())
 			cmd.SetOut(&output)
 			cmd.SetErr(&outputErr)
 			err := inboxFlush(scenario.inboxBuilder)(cmd, scenario.args)
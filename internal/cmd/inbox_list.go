@@ -14,16 +14,19 @@ var inboxListCmd = &cobra.Command{
 
 func inboxList(inboxBuilder inboxBuilder) cobraCmd {
 	return func(cmd *cobra.Command, args []string) error {
-		identifier := normalizeInboxName(args[0])
+		identifier, err := normalizeInboxName(args[0])
+		if err != nil {
+			return err
+		}
 		offset, err := parseOffset(args[1])
 		if err != nil {
 			return err
 		}
-		in, err := inboxBuilder(identifier)
+		in, err := inboxBuilder(cmd.Context(), identifier)
 		if err != nil {
 			return err
 		}
-		if err := in.ParseInboxPages(offset); err != nil {
+		if err := in.ParseInboxPages(cmd.Context(), offset); err != nil {
 			return err
 		}
 
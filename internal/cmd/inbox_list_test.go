@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"testing"
 
@@ -22,6 +23,8 @@ type InboxMock struct {
 	flushError                 error
 	deleteIntArgument          int
 	deleteError                error
+	sendArguments              []string
+	sendError                  error
 	coloured                   string
 	colouredErr                error
 	json                       string
@@ -36,25 +39,30 @@ func (i *InboxMock) GetMails() []inbox.InboxItem {
 	return i.items
 }
 
-func (i *InboxMock) ParseInboxPages(parseInboxPagesIntArgument int) error {
+func (i *InboxMock) ParseInboxPages(ctx context.Context, parseInboxPagesIntArgument int) error {
 	i.parseInboxPagesIntArgument = parseInboxPagesIntArgument
 	return i.parseInboxPagesError
 }
 
-func (i *InboxMock) Fetch(fetchIntArgument int) (inbox.Render, error) {
+func (i *InboxMock) Fetch(ctx context.Context, fetchIntArgument int) (inbox.Render, error) {
 	i.fetchIntArgument = fetchIntArgument
 	return i.fetchMail, i.fetchError
 }
 
-func (i *InboxMock) Flush() error {
+func (i *InboxMock) Flush(ctx context.Context) error {
 	return i.flushError
 }
 
-func (i *InboxMock) Delete(deleteIntArgument int) error {
+func (i *InboxMock) Delete(ctx context.Context, deleteIntArgument int) error {
 	i.deleteIntArgument = deleteIntArgument
 	return i.deleteError
 }
 
+func (i *InboxMock) Send(ctx context.Context, from string, to string, subject string, body string) error {
+	i.sendArguments = []string{from, to, subject, body}
+	return i.sendError
+}
+
 func (i *InboxMock) Coloured() (string, error) {
 	return i.coloured, i.colouredErr
 }
@@ -78,7 +86,7 @@ func TestInboxList(t *testing.T) {
 			name:        "No mails found",
 			args:        []string{"test", "1"},
 			errExpected: errors.New("inbox is empty"),
-			inboxBuilder: func(name string) (Inbox, error) {
+			inboxBuilder: func(ctx context.Context, name string) (Inbox, error) {
 				mock := &InboxMock{}
 				mock.colouredErr = errors.New("inbox is empty")
 				return mock, nil
@@ -87,8 +95,8 @@ func TestInboxList(t *testing.T) {
 		{
 			name:        "Failure when parsing offset",
 			args:        []string{"test", "-1"},
-			errExpected: errors.New(`offset "-1" must be greater than 0`),
-			inboxBuilder: func(name string) (Inbox, error) {
+			errExpected: &inbox.OffsetError{Offset: "-1", Reason: "must be greater than 0"},
+			inboxBuilder: func(ctx context.Context, name string) (Inbox, error) {
 				mock := &InboxMock{}
 				mock.items = []inbox.InboxItem{}
 				return mock, nil
@@ -98,7 +106,7 @@ func TestInboxList(t *testing.T) {
 			name:        "An error is thrown in inbox builder",
 			args:        []string{"test", "1"},
 			errExpected: errors.New("inbox builder error"),
-			inboxBuilder: func(name string) (Inbox, error) {
+			inboxBuilder: func(ctx context.Context, name string) (Inbox, error) {
 				mock := &InboxMock{}
 				return mock, errors.New("inbox builder error")
 			},
@@ -107,7 +115,7 @@ func TestInboxList(t *testing.T) {
 			name:        "An error is thrown in parse inbox pages",
 			args:        []string{"test", "1"},
 			errExpected: errors.New("inbox pages error"),
-			inboxBuilder: func(name string) (Inbox, error) {
+			inboxBuilder: func(ctx context.Context, name string) (Inbox, error) {
 				mock := &InboxMock{parseInboxPagesError: errors.New("inbox pages error")}
 				return mock, nil
 			},
@@ -115,7 +123,7 @@ func TestInboxList(t *testing.T) {
 		{
 			name: "Render inbox",
 			args: []string{"test", "1"},
-			inboxBuilder: func(name string) (Inbox, error) {
+			inboxBuilder: func(ctx context.Context, name string) (Inbox, error) {
 				mock := &InboxMock{}
 				mock.coloured = ` 1 name123 <test123@protonmail.com>
    title`
@@ -134,6 +142,7 @@ func TestInboxList(t *testing.T) {
 			var output bytes.Buffer
 			var outputErr bytes.Buffer
 			cmd := &cobra.Command{}
+			cmd.SetContext(context.Background())
 			cmd.SetOut(&output)
 			cmd.SetErr(&outputErr)
 			err := inboxList(scenario.inboxBuilder)(cmd, scenario.args)
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/antham/yogo/v4/internal/address"
+	"github.com/antham/yogo/v4/internal/client"
+	"github.com/spf13/cobra"
+)
+
+// maxInboxNameLength is the longest inbox name accepted by yopmail
+const maxInboxNameLength = 25
+const minRandomLength = 6
+const newInboxAttempts = 5
+
+var inboxPrefixRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+var newPrefix = ""
+var newDomain = "yopmail.com"
+var newLength = 10
+var newCheck = false
+
+var inboxNewCmd = &cobra.Command{
+	Use:   "new",
+	Short: "Generate a random inbox name",
+	Long: `Generate a random inbox name.
+
+The name is made of the prefix followed by random lowercase letters and
+digits, with the default length two names collide once in 10^15 draws.
+With --check, the inbox is fetched to make sure it is empty, a new name
+is drawn otherwise.
+
+The name and the address are written on stdout to be used in scripts.`,
+	RunE: inboxNew(newInboxes[client.MailHTMLDoc]),
+	Args: cobra.NoArgs,
+}
+
+func inboxNew(inboxesBuilder inboxesBuilder) cobraCmd {
+	return func(cmd *cobra.Command, args []string) error {
+		prefix := strings.ToLower(newPrefix)
+		if err := checkNewInboxFlags(prefix); err != nil {
+			return err
+		}
+		var inboxBuilder func(string) Inbox
+		if newCheck {
+			var err error
+			if inboxBuilder, err = inboxesBuilder(cmd.Context()); err != nil {
+				return err
+			}
+		}
+		name := ""
+		for attempt := 1; ; attempt++ {
+			name = generateInboxName(prefix, newLength)
+			if !newCheck {
+				break
+			}
+			in := inboxBuilder(name)
+			if err := in.ParseInboxPages(cmd.Context(), 1); err != nil {
+				return err
+			}
+			if in.Count() == 0 {
+				break
+			}
+			if attempt == newInboxAttempts {
+				return fmt.Errorf("no empty inbox found after %d attempts, increase the length", attempt)
+			}
+		}
+
+		address := name + "@" + strings.ToLower(newDomain)
+		var output string
+		if dumpJSON {
+			b, err := json.Marshal(struct {
+				Name    string `json:"name"`
+				Address string `json:"address"`
+			}{name, address})
+			if err != nil {
+				return err
+			}
+			output = string(b)
+		} else {
+			output = name + "\n" + address
+		}
+		_, err := fmt.Fprintln(cmd.OutOrStdout(), output)
+		return err
+	}
+}
+
+func checkNewInboxFlags(prefix string) error {
+	if prefix != "" && !inboxPrefixRegexp.MatchString(prefix) {
+		return fmt.Errorf(`prefix "%s" must start with a letter or a digit followed by letters, digits, ".", "-" or "_"`, newPrefix)
+	}
+	if newLength < minRandomLength {
+		return fmt.Errorf("length must be at least %d", minRandomLength)
+	}
+	if len(prefix)+newLength > maxInboxNameLength {
+		return fmt.Errorf("prefix and length can't exceed %d characters", maxInboxNameLength)
+	}
+	if !slices.Contains(knownDomains(), strings.ToLower(newDomain)) {
+		return domainError(&address.DomainError{Domain: newDomain})
+	}
+	return nil
+}
+
+// generateInboxName draws the random part of an inbox name from
+// crypto/rand to not depend on a seed shared between jobs, it
+// is made of lowercase letters and digits from 2 to 7
+func generateInboxName(prefix string, length int) string {
+	return prefix + strings.ToLower(rand.Text())[:length]
+}
+
+func init() {
+	inboxNewCmd.Flags().StringVar(&newPrefix, "prefix", "", "Prefix of the inbox name")
+	inboxNewCmd.Flags().StringVar(&newDomain, "domain", newDomain, "Domain of the address, run \"yogo domains\" to list them")
+	inboxNewCmd.Flags().IntVar(&newLength, "length", newLength, "Number of random characters in the inbox name")
+	inboxNewCmd.Flags().BoolVar(&newCheck, "check", false, "Check the inbox is empty")
+	inboxCmd.AddCommand(inboxNewCmd)
+}
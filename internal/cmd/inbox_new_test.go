@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInboxNew(t *testing.T) {
+	type scenario struct {
+		name         string
+		prefix       string
+		domain       string
+		length       int
+		check        bool
+		json         bool
+		inboxBuilder func(*[]string) inboxesBuilder
+		test         func(output string, names []string, err error)
+	}
+
+	emptyInbox := func(names *[]string) inboxesBuilder {
+		return func(ctx context.Context) (func(string) Inbox, error) {
+			return func(name string) Inbox {
+				*names = append(*names, name)
+				return &InboxMock{}
+			}, nil
+		}
+	}
+
+	scenarios := []scenario{
+		{
+			name:   "Invalid prefix",
+			prefix: "-ci",
+			test: func(output string, names []string, err error) {
+				assert.EqualError(t, err, `prefix "-ci" must start with a letter or a digit followed by letters, digits, ".", "-" or "_"`)
+			},
+		},
+		{
+			name:   "Length too short",
+			length: 5,
+			test: func(output string, names []string, err error) {
+				assert.EqualError(t, err, "length must be at least 6")
+			},
+		},
+		{
+			name:   "Name too long",
+			prefix: "integration-tests-",
+			test: func(output string, names []string, err error) {
+				assert.EqualError(t, err, "prefix and length can't exceed 25 characters")
+			},
+		},
+		{
+			name:   "Foreign domain",
+			domain: "example.com",
+			test: func(output string, names []string, err error) {
+				assert.EqualError(t, err, `"example.com" is not a yopmail domain, run "yogo domains" to list them`)
+			},
+		},
+		{
+			name:   "Inbox generated",
+			prefix: "CI-",
+			test: func(output string, names []string, err error) {
+				assert.NoError(t, err)
+				assert.Regexp(t, regexp.MustCompile(`^(ci-[a-z2-7]{10})\n(ci-[a-z2-7]{10})@yopmail\.com\n$`), output)
+				lines := strings.Split(output, "\n")
+				assert.Equal(t, lines[0]+"@yopmail.com", lines[1])
+				assert.Empty(t, names)
+			},
+		},
+		{
+			name:         "Inbox generated and checked",
+			domain:       "yopmail.fr",
+			length:       25,
+			check:        true,
+			json:         true,
+			inboxBuilder: emptyInbox,
+			test: func(output string, names []string, err error) {
+				assert.NoError(t, err)
+				var result struct {
+					Name    string `json:"name"`
+					Address string `json:"address"`
+				}
+				assert.NoError(t, json.Unmarshal([]byte(output), &result))
+				assert.Len(t, result.Name, 25)
+				assert.Equal(t, result.Name+"@yopmail.fr", result.Address)
+				assert.Equal(t, []string{result.Name}, names)
+			},
+		},
+		{
+			name:  "Inboxes never empty",
+			check: true,
+			inboxBuilder: func(names *[]string) inboxesBuilder {
+				builds := 0
+				return func(ctx context.Context) (func(string) Inbox, error) {
+					builds++
+					assert.Equal(t, 1, builds)
+					return func(name string) Inbox {
+						*names = append(*names, name)
+						return &InboxMock{count: 1}
+					}, nil
+				}
+			},
+			test: func(output string, names []string, err error) {
+				assert.EqualError(t, err, "no empty inbox found after 5 attempts, increase the length")
+				assert.Len(t, names, 5)
+				assert.NotEqual(t, names[0], names[1])
+			},
+		},
+		{
+			name:  "An error is thrown in parse inbox pages",
+			check: true,
+			inboxBuilder: func(names *[]string) inboxesBuilder {
+				return func(ctx context.Context) (func(string) Inbox, error) {
+					return func(name string) Inbox {
+						return &InboxMock{parseInboxPagesError: errors.New("inbox pages error")}
+					}, nil
+				}
+			},
+			test: func(output string, names []string, err error) {
+				assert.EqualError(t, err, "inbox pages error")
+				assert.Empty(t, output)
+			},
+		},
+		{
+			name:  "An error is thrown when building the client",
+			check: true,
+			inboxBuilder: func(names *[]string) inboxesBuilder {
+				return func(ctx context.Context) (func(string) Inbox, error) {
+					return nil, errors.New("client error")
+				}
+			},
+			test: func(output string, names []string, err error) {
+				assert.EqualError(t, err, "client error")
+				assert.Empty(t, output)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			newPrefix = scenario.prefix
+			newDomain = "yopmail.com"
+			if scenario.domain != "" {
+				newDomain = scenario.domain
+			}
+			newLength = 10
+			if scenario.length != 0 {
+				newLength = scenario.length
+			}
+			newCheck = scenario.check
+			dumpJSON = scenario.json
+			defer func() {
+				newPrefix = ""
+				newDomain = "yopmail.com"
+				newLength = 10
+				newCheck = false
+				dumpJSON = false
+			}()
+
+			names := []string{}
+			builder := emptyInbox(&names)
+			if scenario.inboxBuilder != nil {
+				builder = scenario.inboxBuilder(&names)
+			}
+			var output bytes.Buffer
+			cmd := &cobra.Command{}
+			cmd.SetContext(context.Background())
+			cmd.SetOut(&output)
+			err := inboxNew(builder)(cmd, []string{})
+			scenario.test(output.String(), names, err)
+		})
+	}
+}
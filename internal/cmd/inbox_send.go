@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/antham/yogo/v4/internal/client"
+	"github.com/spf13/cobra"
+)
+
+var sendSubject = ""
+var sendBody = ""
+var sendBodyFile = ""
+
+var inboxSendCmd = &cobra.Command{
+	Use:   "send <inbox> <to>",
+	Short: "Send an email from an inbox to another yopmail address",
+	Long: `Send an email from an inbox to another yopmail address.
+
+Yopmail only delivers the emails sent from an inbox to yopmail
+addresses, the recipient is either an inbox name or its address.
+The body is provided with --body or read from --body-file, use
+"-" to read it from the standard input.`,
+	RunE: inboxSend(newInbox[client.MailHTMLDoc]),
+	Args: cobra.ExactArgs(2),
+}
+
+func inboxSend(inboxBuilder inboxBuilder) cobraCmd {
+	return func(cmd *cobra.Command, args []string) error {
+		identifier, from, err := normalizeInbox(args[0])
+		if err != nil {
+			return err
+		}
+		to, err := normalizeRecipient(args[1])
+		if err != nil {
+			return err
+		}
+		body, err := readBody(cmd)
+		if err != nil {
+			return err
+		}
+		in, err := inboxBuilder(cmd.Context(), identifier)
+		if err != nil {
+			return err
+		}
+		if err := in.Send(cmd.Context(), from, to, sendSubject, body); err != nil {
+			return err
+		}
+		cmd.Println(success(fmt.Sprintf(`Email successfully sent to "%s"`, to)))
+		return nil
+	}
+}
+
+// readBody returns the body of the mail to send
+func readBody(cmd *cobra.Command) (string, error) {
+	switch {
+	case sendBody != "" && sendBodyFile != "":
+		return "", errors.New("--body and --body-file can't be used together")
+	case sendBodyFile == "-":
+		b, err := io.ReadAll(cmd.InOrStdin())
+		return string(b), err
+	case sendBodyFile != "":
+		b, err := os.ReadFile(sendBodyFile)
+		return string(b), err
+	}
+	return sendBody, nil
+}
+
+func init() {
+	inboxSendCmd.Flags().StringVar(&sendSubject, "subject", "", "Subject of the email")
+	inboxSendCmd.Flags().StringVar(&sendBody, "body", "", "Body of the email")
+	inboxSendCmd.Flags().StringVar(&sendBodyFile, "body-file", "", `File containing the body of the email, "-" reads the standard input`)
+	inboxCmd.AddCommand(inboxSendCmd)
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/antham/yogo/v4/internal/address"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInboxSend(t *testing.T) {
+	type scenario struct {
+		name          string
+		args          []string
+		body          string
+		bodyFile      string
+		stdin         string
+		errExpected   error
+		sendArguments []string
+		sendError     error
+		output        string
+	}
+
+	bodyFile := filepath.Join(t.TempDir(), "body.txt")
+	assert.NoError(t, os.WriteFile(bodyFile, []byte("body from a file"), 0o600))
+
+	scenarios := []scenario{
+		{
+			name:        "Recipient outside yopmail",
+			args:        []string{"test", "test@example.com"},
+			errExpected: domainError(&address.DomainError{Domain: "example.com"}),
+		},
+		{
+			name:        "Body and body file provided",
+			args:        []string{"test", "test2"},
+			body:        "body",
+			bodyFile:    bodyFile,
+			errExpected: errors.New("--body and --body-file can't be used together"),
+		},
+		{
+			name:          "An error is thrown when sending the email",
+			args:          []string{"test", "test2"},
+			body:          "body",
+			sendArguments: []string{"test@yopmail.com", "test2@yopmail.com", "subject", "body"},
+			sendError:     errors.New("send error"),
+			errExpected:   errors.New("send error"),
+		},
+		{
+			name:          "Email sent with a body",
+			args:          []string{"Test@yopmail.com", "Test2"},
+			body:          "body",
+			sendArguments: []string{"test@yopmail.com", "test2@yopmail.com", "subject", "body"},
+			output: `Email successfully sent to "test2@yopmail.com"
+`,
+		},
+		{
+			name:          "Email sent from an alias domain",
+			args:          []string{"test@yopmail.fr", "test2"},
+			body:          "body",
+			sendArguments: []string{"test@yopmail.fr", "test2@yopmail.com", "subject", "body"},
+			output: `Email successfully sent to "test2@yopmail.com"
+`,
+		},
+		{
+			name:          "Email sent with a body file",
+			args:          []string{"test", "test2@yopmail.com"},
+			bodyFile:      bodyFile,
+			sendArguments: []string{"test@yopmail.com", "test2@yopmail.com", "subject", "body from a file"},
+			output: `Email successfully sent to "test2@yopmail.com"
+`,
+		},
+		{
+			name:          "Email sent with a body read from the standard input",
+			args:          []string{"test", "test2@yopmail.com"},
+			bodyFile:      "-",
+			stdin:         "body from stdin",
+			sendArguments: []string{"test@yopmail.com", "test2@yopmail.com", "subject", "body from stdin"},
+			output: `Email successfully sent to "test2@yopmail.com"
+`,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			sendSubject = "subject"
+			sendBody = scenario.body
+			sendBodyFile = scenario.bodyFile
+			defer func() {
+				sendSubject = ""
+				sendBody = ""
+				sendBodyFile = ""
+			}()
+
+			mock := &InboxMock{sendError: scenario.sendError}
+			var output bytes.Buffer
+			cmd := &cobra.Command{}
+			cmd.SetContext(context.Background())
+			cmd.SetOut(&output)
+			cmd.SetIn(strings.NewReader(scenario.stdin))
+			err := inboxSend(func(ctx context.Context, name string) (Inbox, error) {
+				assert.Equal(t, "test", name)
+				return mock, nil
+			})(cmd, scenario.args)
+			assert.Equal(t, scenario.errExpected, err)
+			assert.Equal(t, scenario.sendArguments, mock.sendArguments)
+			assert.Equal(t, scenario.output, output.String())
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"context"
+
 	"github.com/antham/yogo/v4/internal/client"
+	"github.com/antham/yogo/v4/internal/inbox"
 	"github.com/spf13/cobra"
 )
 
@@ -12,58 +15,70 @@ var inboxShowCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(2),
 }
 
-var inboxSourceCmd = &cobra.Command{
-	Use:   "source <inbox> <offset>",
-	Short: "Show the email source at given position in inbox",
-	RunE:  inboxShow(newInbox[client.MailSourceDoc]),
+var inboxTextCmd = &cobra.Command{
+	Use:   "text <inbox> <offset>",
+	Short: "Show the plain text version of the email rendered by yopmail",
+	RunE:  inboxShow(newInbox[client.MailTextDoc]),
 	Args:  cobra.ExactArgs(2),
 }
 
 func inboxShow(inboxBuilder inboxBuilder) cobraCmd {
 	return func(cmd *cobra.Command, args []string) error {
-		identifier := normalizeInboxName(args[0])
-		offset, err := parseOffset(args[1])
-		if err != nil {
-			return err
-		}
-		in, err := inboxBuilder(identifier)
+		mail, err := fetchMailAt(cmd.Context(), inboxBuilder, args[0], args[1])
 		if err != nil {
 			return err
 		}
-		if err := in.ParseInboxPages(offset); err != nil {
-			return err
-		}
-		if err := checkOffset(in.Count(), offset); err != nil {
-			return err
+		if mail == nil {
+			return nil
 		}
+		return printMail(cmd, mail)
+	}
+}
+
+// fetchMailAt fetches the mail at an offset of an inbox,
+// both are provided as command arguments
+func fetchMailAt(ctx context.Context, inboxBuilder inboxBuilder, name string, position string) (inbox.Render, error) {
+	identifier, err := normalizeInboxName(name)
+	if err != nil {
+		return nil, err
+	}
+	offset, err := parseOffset(position)
+	if err != nil {
+		return nil, err
+	}
+	in, err := inboxBuilder(ctx, identifier)
+	if err != nil {
+		return nil, err
+	}
+	if err := in.ParseInboxPages(ctx, offset); err != nil {
+		return nil, err
+	}
+	if err := checkOffset(in.Count(), offset); err != nil {
+		return nil, err
+	}
+	return in.Fetch(ctx, offset-1)
+}
 
-		mail, err := in.Fetch(offset - 1)
+func printMail(cmd *cobra.Command, mail inbox.Render) error {
+	var err error
+	var output string
+	if dumpJSON {
+		output, err = mail.JSON()
 		if err != nil {
 			return err
 		}
-		if mail == nil {
-			return nil
-		}
-
-		var output string
-		if dumpJSON {
-			output, err = mail.JSON()
-			if err != nil {
-				return err
-			}
-		} else {
-			output, err = mail.Coloured()
-			if err != nil {
-				return err
-			}
+	} else {
+		output, err = mail.Coloured()
+		if err != nil {
+			return err
 		}
-
-		cmd.Println(output)
-		return nil
 	}
+
+	cmd.Println(output)
+	return nil
 }
 
 func init() {
 	inboxCmd.AddCommand(inboxShowCmd)
-	inboxCmd.AddCommand(inboxSourceCmd)
+	inboxCmd.AddCommand(inboxTextCmd)
 }
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -41,7 +42,7 @@ func TestInboxShow(t *testing.T) {
 			name:        "No mails found",
 			args:        []string{"test", "1"},
 			errExpected: errors.New("inbox is empty"),
-			inboxBuilder: func(name string) (Inbox, error) {
+			inboxBuilder: func(ctx context.Context, name string) (Inbox, error) {
 				mock := &InboxMock{}
 				mock.items = []inbox.InboxItem{}
 				return mock, nil
@@ -50,8 +51,8 @@ func TestInboxShow(t *testing.T) {
 		{
 			name:        "Failure when parsing offset",
 			args:        []string{"test", "-1"},
-			errExpected: errors.New(`offset "-1" must be greater than 0`),
-			inboxBuilder: func(name string) (Inbox, error) {
+			errExpected: &inbox.OffsetError{Offset: "-1", Reason: "must be greater than 0"},
+			inboxBuilder: func(ctx context.Context, name string) (Inbox, error) {
 				mock := &InboxMock{}
 				mock.items = []inbox.InboxItem{}
 				return mock, nil
@@ -61,7 +62,7 @@ func TestInboxShow(t *testing.T) {
 			name:        "An error is thrown in inbox builder",
 			args:        []string{"test", "1"},
 			errExpected: errors.New("inbox builder error"),
-			inboxBuilder: func(name string) (Inbox, error) {
+			inboxBuilder: func(ctx context.Context, name string) (Inbox, error) {
 				mock := &InboxMock{}
 				return mock, errors.New("inbox builder error")
 			},
@@ -70,7 +71,7 @@ func TestInboxShow(t *testing.T) {
 			name:        "An error is thrown in parse inbox pages",
 			args:        []string{"test", "1"},
 			errExpected: errors.New("inbox pages error"),
-			inboxBuilder: func(name string) (Inbox, error) {
+			inboxBuilder: func(ctx context.Context, name string) (Inbox, error) {
 				mock := &InboxMock{parseInboxPagesError: errors.New("inbox pages error")}
 				return mock, nil
 			},
@@ -79,7 +80,7 @@ func TestInboxShow(t *testing.T) {
 			name:        "An error is thrown when parsing mail",
 			args:        []string{"test", "1"},
 			errExpected: errors.New("parse email error"),
-			inboxBuilder: func(name string) (Inbox, error) {
+			inboxBuilder: func(ctx context.Context, name string) (Inbox, error) {
 				mock := &InboxMock{parseInboxPagesError: errors.New("parse email error")}
 				mock.count = 1
 				mock.items = []inbox.InboxItem{
@@ -97,9 +98,10 @@ func TestInboxShow(t *testing.T) {
 			},
 		},
 		{
-			name: "Offset to high compared to the number of emails",
-			args: []string{"test", "2"},
-			inboxBuilder: func(name string) (Inbox, error) {
+			name:        "Offset to high compared to the number of emails",
+			args:        []string{"test", "2"},
+			errExpected: &inbox.OffsetError{Offset: "2", Reason: "is greater than the number of mails (1), lower your offset value"},
+			inboxBuilder: func(ctx context.Context, name string) (Inbox, error) {
 				mock := &InboxMock{fetchMail: nil}
 				mock.count = 1
 				mock.items = []inbox.InboxItem{
@@ -120,7 +122,7 @@ func TestInboxShow(t *testing.T) {
 			name:        "No mail found",
 			args:        []string{"test", "1"},
 			errExpected: errors.New("inbox is empty"),
-			inboxBuilder: func(name string) (Inbox, error) {
+			inboxBuilder: func(ctx context.Context, name string) (Inbox, error) {
 				mock := &InboxMock{fetchMail: nil}
 				return mock, nil
 			},
@@ -137,7 +139,7 @@ body
 ---
 
 `,
-			inboxBuilder: func(name string) (Inbox, error) {
+			inboxBuilder: func(ctx context.Context, name string) (Inbox, error) {
 				now, _ := time.Parse("2006-01-02", "2001-01-01")
 				mock := &InboxMock{}
 				mock.count = 1
@@ -175,6 +177,7 @@ body
 			var output bytes.Buffer
 			var outputErr bytes.Buffer
 			cmd := &cobra.Command{}
+			cmd.SetContext(context.Background())
 			cmd.SetOut(&output)
 			cmd.SetErr(&outputErr)
 			err := inboxShow(scenario.inboxBuilder)(cmd, scenario.args)
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/antham/yogo/v4/internal/client"
+	"github.com/antham/yogo/v4/internal/inbox"
+	"github.com/spf13/cobra"
+)
+
+var sourceRaw = false
+var sourceOutput = ""
+
+var inboxSourceCmd = &cobra.Command{
+	Use:   "source <inbox> <offset>",
+	Short: "Show the email source at given position in inbox",
+	Long: `Show the email source at given position in inbox.
+
+The headers are followed by the MIME tree of the email: every part
+is shown with its content type, charset, transfer encoding and its
+text decoded. Use --raw to show the body as it was received.
+
+Use --output to save the email as an .eml file, the message is written
+as it was received with its headers in their original order, "-"
+writes it on stdout.`,
+	RunE: inboxSource(newInbox[client.MailSourceDoc]),
+	Args: cobra.ExactArgs(2),
+}
+
+func inboxSource(inboxBuilder inboxBuilder) cobraCmd {
+	return func(cmd *cobra.Command, args []string) error {
+		mail, err := fetchMailAt(cmd.Context(), inboxBuilder, args[0], args[1])
+		if err != nil {
+			return err
+		}
+		if mail == nil {
+			return nil
+		}
+		m, ok := mail.(*inbox.SourceMail)
+		if !ok {
+			return errors.New("unexpected mail type")
+		}
+		if sourceOutput != "" {
+			return saveSource(cmd, sourceOutput, m)
+		}
+		m.Raw = sourceRaw
+		return printMail(cmd, m)
+	}
+}
+
+func saveSource(cmd *cobra.Command, path string, m *inbox.SourceMail) error {
+	if path == "-" {
+		_, err := cmd.OutOrStdout().Write(m.EML())
+		return err
+	}
+	if err := os.WriteFile(path, m.EML(), 0o644); err != nil {
+		return err
+	}
+	cmd.Println(success(fmt.Sprintf("Email saved to %s", path)))
+	return nil
+}
+
+func init() {
+	inboxSourceCmd.Flags().BoolVar(&sourceRaw, "raw", false, "Show the body as it was received instead of decoding it")
+	inboxSourceCmd.Flags().StringVar(&sourceOutput, "output", "", `Save the email as an .eml file, "-" writes it on stdout`)
+	inboxCmd.AddCommand(inboxSourceCmd)
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antham/yogo/v4/internal/inbox"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInboxSource(t *testing.T) {
+	type scenario struct {
+		name        string
+		args        []string
+		raw         bool
+		json        bool
+		output      string
+		save        bool
+		errExpected error
+		stdout      string
+	}
+
+	scenarios := []scenario{
+		{
+			name:        "Failure when parsing offset",
+			args:        []string{"test", "a"},
+			errExpected: &inbox.OffsetError{Offset: "a", Reason: "must be an integer"},
+		},
+		{
+			name: "Output the decoded mail",
+			args: []string{"test", "1"},
+			stdout: `---
+Content-Transfer-Encoding : quoted-printable
+
+Content-Type              : text/plain; charset=utf-8
+
+---
+[1] text/plain (charset utf-8, quoted-printable, 5 bytes)
+Café
+---
+
+`,
+		},
+		{
+			name: "Output the raw mail",
+			args: []string{"test", "1"},
+			raw:  true,
+			stdout: `---
+Content-Transfer-Encoding : quoted-printable
+
+Content-Type              : text/plain; charset=utf-8
+
+---
+Caf=C3=A9
+---
+
+`,
+		},
+		{
+			name:   "Output the raw mail as JSON",
+			args:   []string{"test", "1"},
+			raw:    true,
+			json:   true,
+			stdout: `{"id":"abcdefg","headers":{"Content-Transfer-Encoding":["quoted-printable"],"Content-Type":["text/plain; charset=utf-8"]},"body":"Caf=C3=A9"}` + "\n",
+		},
+		{
+			name:   "Write the message on stdout",
+			args:   []string{"test", "1"},
+			output: "-",
+			stdout: "Content-Type: text/plain; charset=utf-8\nContent-Transfer-Encoding: quoted-printable\n\nCaf=C3=A9",
+		},
+		{
+			name: "Save the message in a file",
+			args: []string{"test", "1"},
+			save: true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "mail.eml")
+			sourceRaw = scenario.raw
+			sourceOutput = scenario.output
+			if scenario.save {
+				sourceOutput = path
+			}
+			dumpJSON = scenario.json
+			defer func() {
+				sourceRaw = false
+				sourceOutput = ""
+				dumpJSON = false
+			}()
+
+			var output bytes.Buffer
+			cmd := &cobra.Command{}
+			cmd.SetContext(context.Background())
+			cmd.SetOut(&output)
+			err := inboxSource(func(ctx context.Context, name string) (Inbox, error) {
+				if name != "test" {
+					return nil, errors.New("unexpected inbox")
+				}
+				mock := &InboxMock{fetchMail: &inbox.SourceMail{
+					ID: "abcdefg",
+					Headers: map[string][]string{
+						"Content-Type":              {"text/plain; charset=utf-8"},
+						"Content-Transfer-Encoding": {"quoted-printable"},
+					},
+					Body:    "Caf=C3=A9",
+					Message: "Content-Type: text/plain; charset=utf-8\nContent-Transfer-Encoding: quoted-printable\n\nCaf=C3=A9",
+				}}
+				mock.count = 1
+				mock.items = []inbox.InboxItem{{ID: "abcdefg"}}
+				return mock, nil
+			})(cmd, scenario.args)
+			assert.Equal(t, scenario.errExpected, err)
+			if !scenario.save {
+				assert.Equal(t, scenario.stdout, output.String())
+				assert.NoFileExists(t, path)
+				return
+			}
+			b, err := os.ReadFile(path)
+			assert.NoError(t, err)
+			assert.Equal(t, "Content-Type: text/plain; charset=utf-8\nContent-Transfer-Encoding: quoted-printable\n\nCaf=C3=A9", string(b))
+			assert.Contains(t, output.String(), "Email saved to "+path)
+		})
+	}
+}
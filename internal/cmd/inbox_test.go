@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"testing"
 
 	"github.com/antham/yogo/v4/internal/client"
@@ -8,7 +9,7 @@ import (
 )
 
 func TestNewInbox(t *testing.T) {
-	in, err := newInbox[client.MailHTMLDoc]("test")
+	in, err := newInbox[client.MailHTMLDoc](context.Background(), "test")
 	assert.NoError(t, err)
 	assert.NotEmpty(t, in)
 }
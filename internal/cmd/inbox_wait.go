@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/antham/yogo/v4/internal/client"
+	"github.com/antham/yogo/v4/internal/inbox"
+	"github.com/spf13/cobra"
+)
+
+// errWaitTimeout is returned when no mail matched before the timeout
+var errWaitTimeout = errors.New("no matching email received before the timeout")
+
+var waitFrom = ""
+var waitSubject = ""
+var waitBody = ""
+var waitTimeout = 2 * time.Minute
+var waitInterval = 5 * time.Second
+var waitIncludeExisting = false
+
+var inboxWaitCmd = &cobra.Command{
+	Use:   "wait <inbox>",
+	Short: "Wait for an email matching the given criteria",
+	Long: `Wait for an email matching the given criteria.
+
+The inbox is polled every --interval until an email matches all the
+regular expressions provided with --from (the sender name and address),
+--subject and --body, the first matching email is printed. Use (?i) at
+the start of an expression to ignore the case.
+
+The emails already in the inbox when the command starts are ignored
+unless --include-existing is provided. The command exits with the code
+9 when no email matched before --timeout.`,
+	RunE: inboxWait(newInbox[client.MailHTMLDoc]),
+	Args: cobra.ExactArgs(1),
+}
+
+// mailMatcher holds the criteria a mail must match, a nil
+// regular expression matches everything
+type mailMatcher struct {
+	from    *regexp.Regexp
+	subject *regexp.Regexp
+	body    *regexp.Regexp
+}
+
+func newMailMatcher(from string, subject string, body string) (mailMatcher, error) {
+	m := mailMatcher{}
+	for _, e := range []struct {
+		flag       string
+		expression string
+		regexp     **regexp.Regexp
+	}{
+		{"from", from, &m.from},
+		{"subject", subject, &m.subject},
+		{"body", body, &m.body},
+	} {
+		if e.expression == "" {
+			continue
+		}
+		r, err := regexp.Compile(e.expression)
+		if err != nil {
+			return m, fmt.Errorf("--%s is not a valid regular expression : %w", e.flag, err)
+		}
+		*e.regexp = r
+	}
+	return m, nil
+}
+
+func (m mailMatcher) match(mail *inbox.HTMLMail) bool {
+	from := ""
+	if mail.Sender != nil {
+		from = fmt.Sprintf("%s <%s>", mail.Sender.Name, mail.Sender.Mail)
+	}
+	return (m.from == nil || m.from.MatchString(from)) &&
+		(m.subject == nil || m.subject.MatchString(mail.Subject)) &&
+		(m.body == nil || m.body.MatchString(mail.Body))
+}
+
+func inboxWait(inboxBuilder inboxBuilder) cobraCmd {
+	return func(cmd *cobra.Command, args []string) error {
+		identifier, err := normalizeInboxName(args[0])
+		if err != nil {
+			return err
+		}
+		matcher, err := newMailMatcher(waitFrom, waitSubject, waitBody)
+		if err != nil {
+			return err
+		}
+		if waitInterval <= 0 {
+			return errors.New("interval must be greater than 0")
+		}
+		ctx := cmd.Context()
+		if waitTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, waitTimeout)
+			defer cancel()
+		}
+		in, err := inboxBuilder(ctx, identifier)
+		if err != nil {
+			return waitError(ctx, err)
+		}
+
+		var found inbox.Render
+		err = pollNewMails(ctx, in, waitInterval, !waitIncludeExisting, func(offset int) (bool, error) {
+			r, err := in.Fetch(ctx, offset)
+			if err != nil {
+				return false, err
+			}
+			mail, ok := r.(*inbox.HTMLMail)
+			if !ok {
+				return false, errors.New("unexpected mail type")
+			}
+			if !matcher.match(mail) {
+				return false, nil
+			}
+			found = r
+			return true, nil
+		})
+		if err != nil {
+			return waitError(ctx, err)
+		}
+
+		var output string
+		if dumpJSON {
+			output, err = found.JSON()
+		} else {
+			output, err = found.Coloured()
+		}
+		if err != nil {
+			return err
+		}
+		// stdout is used rather than Println to capture the mail in scripts
+		_, err = fmt.Fprintln(cmd.OutOrStdout(), output)
+		return err
+	}
+}
+
+// waitError turns the end of the wait into errWaitTimeout
+func waitError(ctx context.Context, err error) error {
+	if errors.Is(err, context.DeadlineExceeded) && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return errWaitTimeout
+	}
+	return err
+}
+
+func init() {
+	inboxWaitCmd.Flags().StringVar(&waitFrom, "from", "", "Regular expression matching the sender name and address")
+	inboxWaitCmd.Flags().StringVar(&waitSubject, "subject", "", "Regular expression matching the subject")
+	inboxWaitCmd.Flags().StringVar(&waitBody, "body", "", "Regular expression matching the body")
+	inboxWaitCmd.Flags().DurationVar(&waitTimeout, "timeout", waitTimeout, "Maximum time spent waiting, 0 waits forever")
+	inboxWaitCmd.Flags().DurationVar(&waitInterval, "interval", waitInterval, "Delay between two polls of the inbox")
+	inboxWaitCmd.Flags().BoolVar(&waitIncludeExisting, "include-existing", false, "Match the emails already in the inbox when the command starts")
+	inboxCmd.AddCommand(inboxWaitCmd)
+}
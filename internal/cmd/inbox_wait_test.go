@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/antham/yogo/v4/internal/address"
+	"github.com/antham/yogo/v4/internal/inbox"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+// waitInboxMock returns the full mails matching the polled items
+type waitInboxMock struct {
+	watchInboxMock
+	mails map[string]*inbox.HTMLMail
+}
+
+func (i *waitInboxMock) Fetch(ctx context.Context, offset int) (inbox.Render, error) {
+	i.fetched = append(i.fetched, offset)
+	if i.fetchError != nil {
+		return nil, i.fetchError
+	}
+	return i.mails[i.items[offset].ID], nil
+}
+
+func TestInboxWait(t *testing.T) {
+	type scenario struct {
+		name            string
+		args            []string
+		from            string
+		subject         string
+		body            string
+		timeout         time.Duration
+		includeExisting bool
+		json            bool
+		mock            *waitInboxMock
+		err             error
+		output          string
+		fetched         []int
+	}
+
+	a := inbox.InboxItem{ID: "a", Subject: "Welcome"}
+	b := inbox.InboxItem{ID: "b", Subject: "Confirm your account"}
+	c := inbox.InboxItem{ID: "c", Subject: "Confirm your account"}
+	d := inbox.InboxItem{ID: "d", Subject: "Confirm your account"}
+	e := inbox.InboxItem{ID: "e", Subject: "Confirm your acc..."}
+	mails := map[string]*inbox.HTMLMail{
+		"a": {ID: "a", Sender: &inbox.MailSender{Name: "Shop", Mail: "shop@example.com"}, Subject: "Welcome", Body: "Hello"},
+		"b": {ID: "b", Sender: &inbox.MailSender{Name: "Spam", Mail: "spam@example.org"}, Subject: "Confirm your account", Body: "Code 1234"},
+		"d": {ID: "d", Sender: &inbox.MailSender{Name: "Shop", Mail: "shop@example.com"}, Subject: "Confirm your account", Body: "Code 5678"},
+		"e": {ID: "e", Subject: "Confirm your account"},
+	}
+
+	scenarios := []scenario{
+		{
+			name: "Foreign domain",
+			args: []string{"test@example.com"},
+			mock: &waitInboxMock{},
+			err:  domainError(&address.DomainError{Domain: "example.com"}),
+		},
+		{
+			name:    "Invalid regular expression",
+			args:    []string{"test"},
+			subject: "(",
+			mock:    &waitInboxMock{},
+			err:     errors.New("--subject is not a valid regular expression : error parsing regexp: missing closing ): `(`"),
+		},
+		{
+			name: "An error is thrown in parse inbox pages",
+			args: []string{"test"},
+			mock: &waitInboxMock{watchInboxMock: watchInboxMock{InboxMock: InboxMock{parseInboxPagesError: errors.New("inbox pages error")}}},
+			err:  errors.New("inbox pages error"),
+		},
+		{
+			name: "An error is thrown in fetch",
+			args: []string{"test"},
+			mock: &waitInboxMock{watchInboxMock: watchInboxMock{
+				InboxMock: InboxMock{fetchError: errors.New("fetch error")},
+				polls:     [][]inbox.InboxItem{{}, {a}},
+			}},
+			err:     errors.New("fetch error"),
+			fetched: []int{0},
+		},
+		{
+			name:    "First new mail matching all the criteria",
+			args:    []string{"test"},
+			from:    `@example\.com`,
+			subject: "(?i)confirm",
+			body:    `Code \d+`,
+			json:    true,
+			mock: &waitInboxMock{
+				watchInboxMock: watchInboxMock{polls: [][]inbox.InboxItem{
+					{c},
+					{a, c},
+					{b, a, c},
+					{d, b, a, c},
+				}},
+				mails: mails,
+			},
+			output:  `{"id":"d","sender":{"mail":"shop@example.com","name":"Shop"},"subject":"Confirm your account","body":"Code 5678","isSPAM":false}` + "\n",
+			fetched: []int{0, 0, 0},
+		},
+		{
+			name:    "Truncated subject in the inbox",
+			args:    []string{"test"},
+			subject: "account$",
+			json:    true,
+			mock: &waitInboxMock{
+				watchInboxMock: watchInboxMock{polls: [][]inbox.InboxItem{{}, {e}}},
+				mails:          mails,
+			},
+			output:  `{"id":"e","subject":"Confirm your account","isSPAM":false}` + "\n",
+			fetched: []int{0},
+		},
+		{
+			name:            "Existing mail included",
+			args:            []string{"test"},
+			subject:         "Welcome",
+			includeExisting: true,
+			json:            true,
+			mock: &waitInboxMock{
+				watchInboxMock: watchInboxMock{polls: [][]inbox.InboxItem{{a}}},
+				mails:          mails,
+			},
+			output:  `{"id":"a","sender":{"mail":"shop@example.com","name":"Shop"},"subject":"Welcome","body":"Hello","isSPAM":false}` + "\n",
+			fetched: []int{0},
+		},
+		{
+			name:    "Timeout reached",
+			args:    []string{"test"},
+			subject: "Welcome",
+			timeout: 20 * time.Millisecond,
+			mock: &waitInboxMock{
+				watchInboxMock: watchInboxMock{polls: [][]inbox.InboxItem{{a}, {b, a}}},
+				mails:          mails,
+			},
+			err:     errWaitTimeout,
+			fetched: []int{0},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			waitInterval = time.Millisecond
+			waitFrom = scenario.from
+			waitSubject = scenario.subject
+			waitBody = scenario.body
+			waitTimeout = scenario.timeout
+			waitIncludeExisting = scenario.includeExisting
+			dumpJSON = scenario.json
+			defer func() {
+				waitInterval = 5 * time.Second
+				waitFrom = ""
+				waitSubject = ""
+				waitBody = ""
+				waitTimeout = 2 * time.Minute
+				waitIncludeExisting = false
+				dumpJSON = false
+			}()
+
+			var output bytes.Buffer
+			cmd := &cobra.Command{}
+			cmd.SetContext(context.Background())
+			cmd.SetOut(&output)
+			err := inboxWait(func(ctx context.Context, name string) (Inbox, error) {
+				return scenario.mock, nil
+			})(cmd, scenario.args)
+			if scenario.err != nil {
+				assert.EqualError(t, err, scenario.err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, scenario.output, output.String())
+			assert.Equal(t, scenario.fetched, scenario.mock.fetched)
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/antham/yogo/v4/internal/client"
+	"github.com/antham/yogo/v4/internal/inbox"
+	"github.com/spf13/cobra"
+)
+
+// watchLimit is the number of mails polled, it keeps the
+// polling to the first page of the inbox
+const watchLimit = 15
+
+var watchInterval = 15 * time.Second
+var watchCount = 0
+var watchTimeout time.Duration
+var watchBody = false
+
+var inboxWatchCmd = &cobra.Command{
+	Use:   "watch <inbox>",
+	Short: "Follow an inbox and print the new emails as they arrive",
+	Long: `Follow an inbox and print the new emails as they arrive.
+
+The inbox is polled every --interval, the emails already in the inbox
+when the command starts are not printed. With --json, every email is
+printed on its own line. The command runs until it is interrupted,
+--count stops it after a number of emails and --timeout after a delay.`,
+	RunE: inboxWatch(newInbox[client.MailHTMLDoc]),
+	Args: cobra.ExactArgs(1),
+}
+
+func inboxWatch(inboxBuilder inboxBuilder) cobraCmd {
+	return func(cmd *cobra.Command, args []string) error {
+		identifier, err := normalizeInboxName(args[0])
+		if err != nil {
+			return err
+		}
+		if watchInterval <= 0 {
+			return errors.New("interval must be greater than 0")
+		}
+		ctx := cmd.Context()
+		if watchTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, watchTimeout)
+			defer cancel()
+		}
+		in, err := inboxBuilder(ctx, identifier)
+		if err != nil {
+			return ignoreTimeout(ctx, err)
+		}
+
+		received := 0
+		err = pollNewMails(ctx, in, watchInterval, true, func(offset int) (bool, error) {
+			received++
+			if err := printWatchedMail(ctx, cmd, in, offset, received); err != nil {
+				return false, err
+			}
+			return watchCount > 0 && received >= watchCount, nil
+		})
+		return ignoreTimeout(ctx, err)
+	}
+}
+
+// pollNewMails polls an inbox until it is told to stop or until the
+// context is done, onMail is called with the offset of every mail
+// not seen yet, the oldest first, mails already in the inbox on the
+// first poll are skipped when skipExisting is true
+func pollNewMails(ctx context.Context, in Inbox, interval time.Duration, skipExisting bool, onMail func(int) (bool, error)) error {
+	seen := map[string]bool{}
+	for poll := 0; ; poll++ {
+		if err := in.ParseInboxPages(ctx, watchLimit); err != nil {
+			return err
+		}
+		mails := in.GetMails()
+		// the inbox lists the most recent mails first
+		for offset := len(mails) - 1; offset >= 0; offset-- {
+			if seen[mails[offset].ID] {
+				continue
+			}
+			seen[mails[offset].ID] = true
+			if poll == 0 && skipExisting {
+				continue
+			}
+			done, err := onMail(offset)
+			if err != nil || done {
+				return err
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// printWatchedMail writes a new mail on stdout as soon as it
+// arrives, its full content is fetched when requested
+func printWatchedMail(ctx context.Context, cmd *cobra.Command, in Inbox, offset int, index int) error {
+	var output string
+	var err error
+	switch {
+	case watchBody:
+		var mail inbox.Render
+		mail, err = in.Fetch(ctx, offset)
+		if err != nil {
+			return err
+		}
+		if dumpJSON {
+			output, err = mail.JSON()
+		} else {
+			output, err = mail.Coloured()
+		}
+	case dumpJSON:
+		var b []byte
+		b, err = json.Marshal(in.GetMails()[offset])
+		output = string(b)
+	default:
+		output, err = inbox.ColouredItem(index, in.GetMails()[offset])
+		output = strings.TrimRight(output, "\n")
+	}
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(cmd.OutOrStdout(), output)
+	return err
+}
+
+// ignoreTimeout ends the watch without error once the timeout is reached
+func ignoreTimeout(ctx context.Context, err error) error {
+	if errors.Is(err, context.DeadlineExceeded) && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil
+	}
+	return err
+}
+
+func init() {
+	inboxWatchCmd.Flags().DurationVar(&watchInterval, "interval", watchInterval, "Delay between two polls of the inbox")
+	inboxWatchCmd.Flags().IntVar(&watchCount, "count", 0, "Stop after this number of new emails, 0 never stops")
+	inboxWatchCmd.Flags().DurationVar(&watchTimeout, "timeout", 0, "Stop after this delay, 0 never stops")
+	inboxWatchCmd.Flags().BoolVar(&watchBody, "body", false, "Fetch and print the full email")
+	inboxCmd.AddCommand(inboxWatchCmd)
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/antham/yogo/v4/internal/address"
+	"github.com/antham/yogo/v4/internal/inbox"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+// watchInboxMock returns a new state of the inbox on every poll
+type watchInboxMock struct {
+	InboxMock
+	polls   [][]inbox.InboxItem
+	fetched []int
+}
+
+func (i *watchInboxMock) ParseInboxPages(ctx context.Context, limit int) error {
+	i.parseInboxPagesIntArgument = limit
+	if i.parseInboxPagesError != nil {
+		return i.parseInboxPagesError
+	}
+	i.items = i.polls[0]
+	if len(i.polls) > 1 {
+		i.polls = i.polls[1:]
+	}
+	return nil
+}
+
+func (i *watchInboxMock) Fetch(ctx context.Context, offset int) (inbox.Render, error) {
+	i.fetched = append(i.fetched, offset)
+	item := i.items[offset]
+	return MailMock{coloured: "mail " + item.ID, json: `{"id":"` + item.ID + `"}`}, i.fetchError
+}
+
+func TestInboxWatch(t *testing.T) {
+	type scenario struct {
+		name    string
+		args    []string
+		count   int
+		timeout time.Duration
+		body    bool
+		json    bool
+		mock    *watchInboxMock
+		err     error
+		output  string
+		fetched []int
+	}
+
+	a := inbox.InboxItem{ID: "a", Subject: "subject a", Sender: &inbox.Sender{Mail: "a@example.com"}}
+	b := inbox.InboxItem{ID: "b", Subject: "subject b", Sender: &inbox.Sender{Name: "B", Mail: "b@example.com"}}
+	c := inbox.InboxItem{ID: "c", Subject: "subject c"}
+
+	scenarios := []scenario{
+		{
+			name: "Foreign domain",
+			args: []string{"test@example.com"},
+			mock: &watchInboxMock{},
+			err:  domainError(&address.DomainError{Domain: "example.com"}),
+		},
+		{
+			name: "An error is thrown in parse inbox pages",
+			args: []string{"test"},
+			mock: &watchInboxMock{InboxMock: InboxMock{parseInboxPagesError: errors.New("inbox pages error")}},
+			err:  errors.New("inbox pages error"),
+		},
+		{
+			name:  "New mails printed until the count is reached",
+			args:  []string{"test"},
+			count: 2,
+			mock: &watchInboxMock{polls: [][]inbox.InboxItem{
+				{a},
+				{a},
+				{c, b, a},
+			}},
+			output: ` 1 B <b@example.com>
+   subject b
+ 2 [no data to display]
+   subject c
+`,
+		},
+		{
+			name:  "New mails printed as JSON lines with their body",
+			args:  []string{"test"},
+			count: 2,
+			body:  true,
+			json:  true,
+			mock: &watchInboxMock{polls: [][]inbox.InboxItem{
+				{},
+				{b},
+				{c, b},
+			}},
+			output: `{"id":"b"}
+{"id":"c"}
+`,
+			fetched: []int{0, 0},
+		},
+		{
+			name:  "Summaries printed as JSON lines",
+			args:  []string{"test"},
+			count: 1,
+			json:  true,
+			mock: &watchInboxMock{polls: [][]inbox.InboxItem{
+				{},
+				{a},
+			}},
+			output: `{"id":"a","sender":{"mail":"a@example.com"},"subject":"subject a","isSPAM":false}
+`,
+		},
+		{
+			name:    "Timeout reached",
+			args:    []string{"test"},
+			timeout: 20 * time.Millisecond,
+			mock: &watchInboxMock{polls: [][]inbox.InboxItem{
+				{a},
+				{b, a},
+			}},
+			output: ` 1 B <b@example.com>
+   subject b
+`,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			watchInterval = time.Millisecond
+			watchCount = scenario.count
+			watchTimeout = scenario.timeout
+			watchBody = scenario.body
+			dumpJSON = scenario.json
+			defer func() {
+				watchInterval = 15 * time.Second
+				watchCount = 0
+				watchTimeout = 0
+				watchBody = false
+				dumpJSON = false
+			}()
+
+			var output bytes.Buffer
+			cmd := &cobra.Command{}
+			cmd.SetContext(context.Background())
+			cmd.SetOut(&output)
+			err := inboxWatch(func(ctx context.Context, name string) (Inbox, error) {
+				return scenario.mock, nil
+			})(cmd, scenario.args)
+			assert.Equal(t, scenario.err, err)
+			assert.Equal(t, scenario.output, output.String())
+			assert.Equal(t, scenario.fetched, scenario.mock.fetched)
+		})
+	}
+}
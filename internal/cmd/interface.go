@@ -1,15 +1,18 @@
 package cmd
 
 import (
+	"context"
+
 	"github.com/antham/yogo/v4/internal/inbox"
 )
 
 type Inbox interface {
 	inbox.Render
-	ParseInboxPages(int) error
+	ParseInboxPages(context.Context, int) error
 	Count() int
 	GetMails() []inbox.InboxItem
-	Fetch(int) (inbox.Render, error)
-	Flush() error
-	Delete(int) error
+	Fetch(context.Context, int) (inbox.Render, error)
+	Flush(context.Context) error
+	Delete(context.Context, int) error
+	Send(context.Context, string, string, string, string) error
 }
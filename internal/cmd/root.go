@@ -1,8 +1,16 @@
 package cmd
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
 
+	"github.com/antham/yogo/v4/internal/client"
 	"github.com/spf13/cobra"
 )
 
@@ -10,17 +18,179 @@ type cobraCmd func(*cobra.Command, []string) error
 
 var dumpJSON = false
 var enableDebugMode = false
+var debugFile = ""
+var debugOutput *os.File
+var retryPolicy = client.DefaultRetryPolicy()
+var rateLimit = client.DefaultRateLimit()
+var captchaPolicy = client.CaptchaPolicy{}
+var baseURL = ""
+var sessionFile = ""
+var harFile = ""
+var harRecorder *client.HARRecorder
+var timezone = ""
+
+// envFlags maps flags to the environment variable
+// used when the flag is not provided
+var envFlags = map[string]string{
+	"retry-max-attempts": "YOGO_RETRY_MAX_ATTEMPTS",
+	"retry-base-delay":   "YOGO_RETRY_BASE_DELAY",
+	"retry-max-delay":    "YOGO_RETRY_MAX_DELAY",
+	"rate-limit":         "YOGO_RATE_LIMIT",
+	"rate-burst":         "YOGO_RATE_BURST",
+	"base-url":           "YOGO_BASE_URL",
+	"captcha-cooldown":   "YOGO_CAPTCHA_COOLDOWN",
+	"captcha-probe":      "YOGO_CAPTCHA_PROBE",
+	"captcha-deadline":   "YOGO_CAPTCHA_DEADLINE",
+	"session-file":       "YOGO_SESSION_FILE",
+	"debug-file":         "YOGO_DEBUG_FILE",
+	"har":                "YOGO_HAR_FILE",
+	"timezone":           "YOGO_TIMEZONE",
+}
 
 var RootCmd = &cobra.Command{
-	Use:   "yogo",
-	Short: "Interact with yopmail from command-line",
-	Long:  `Check yopmail mails from command line.`,
+	Use:               "yogo",
+	Short:             "Interact with yopmail from command-line",
+	Long:              `Check yopmail mails from command line.`,
+	PersistentPreRunE: loadEnvFlags,
 }
 
 func Execute() {
 	RootCmd.PersistentFlags().BoolVar(&dumpJSON, "json", false, "Dump the output as json")
-	RootCmd.PersistentFlags().BoolVar(&enableDebugMode, "debug", false, "Log all requests/responses")
-	if err := RootCmd.Execute(); err != nil {
-		os.Exit(-1)
+	RootCmd.PersistentFlags().BoolVar(&enableDebugMode, "debug", false, "Log all requests/responses on stderr")
+	RootCmd.PersistentFlags().StringVar(&debugFile, "debug-file", "", "Log all requests/responses in this file instead of stderr")
+	RootCmd.PersistentFlags().IntVar(&retryPolicy.MaxAttempts, "retry-max-attempts", retryPolicy.MaxAttempts, "Maximum number of attempts for a request failing with a transient error")
+	RootCmd.PersistentFlags().DurationVar(&retryPolicy.BaseDelay, "retry-base-delay", retryPolicy.BaseDelay, "Delay before retrying a failed request, doubled on every attempt")
+	RootCmd.PersistentFlags().DurationVar(&retryPolicy.MaxDelay, "retry-max-delay", retryPolicy.MaxDelay, "Maximum delay between two attempts")
+	RootCmd.PersistentFlags().IntVar(&rateLimit.RequestsPerMinute, "rate-limit", rateLimit.RequestsPerMinute, "Maximum number of requests sent per minute, 0 disables the limit")
+	RootCmd.PersistentFlags().IntVar(&rateLimit.Burst, "rate-burst", rateLimit.Burst, "Number of requests that can be sent at once")
+	RootCmd.PersistentFlags().DurationVar(&captchaPolicy.Cooldown, "captcha-cooldown", 0, "Wait this delay when a CAPTCHA is required then resume once it is lifted, 0 fails immediately")
+	RootCmd.PersistentFlags().DurationVar(&captchaPolicy.ProbeInterval, "captcha-probe", 0, "Delay between two checks of the CAPTCHA (default to the cooldown)")
+	RootCmd.PersistentFlags().DurationVar(&captchaPolicy.Deadline, "captcha-deadline", 0, "Maximum time spent waiting for a CAPTCHA to be lifted, 0 waits forever")
+	RootCmd.PersistentFlags().StringVar(&baseURL, "base-url", "", "Replace the yopmail URL, to target a fake server for instance")
+	RootCmd.PersistentFlags().StringVar(&harFile, "har", "", "Record all requests/responses in this HTTP Archive file")
+	RootCmd.PersistentFlags().StringVar(&sessionFile, "session-file", "", "File persisting the session between runs (default to a file in the user cache directory)")
+	RootCmd.PersistentFlags().StringVar(&timezone, "timezone", "", "Timezone of the times displayed in the inboxes, an IANA name like America/New_York (default to Europe/Paris)")
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	err := RootCmd.ExecuteContext(ctx)
+	if debugOutput != nil {
+		debugOutput.Close()
+	}
+	if harRecorder != nil {
+		if err := harRecorder.WriteFile(harFile); err != nil {
+			RootCmd.PrintErrln("Error:", err)
+		}
+	}
+	if errors.Is(err, client.ErrCaptcha) {
+		RootCmd.PrintErrln(info(`Run "yogo captcha <inbox>" to resume the session from a browser`))
+	}
+	if err != nil {
+		stop()
+		os.Exit(exitCode(err))
+	}
+}
+
+// loadEnvFlags populates the flags not set on the command line
+// from their environment variable
+func loadEnvFlags(cmd *cobra.Command, args []string) error {
+	for flag, env := range envFlags {
+		value, ok := os.LookupEnv(env)
+		if !ok || cmd.Flags().Lookup(flag) == nil || cmd.Flags().Changed(flag) {
+			continue
+		}
+		if err := cmd.Flags().Set(flag, value); err != nil {
+			return fmt.Errorf("environment variable %s : %w", env, err)
+		}
+	}
+	return nil
+}
+
+func clientConfig() (client.Config, error) {
+	path, err := sessionFilePath()
+	if err != nil {
+		return client.Config{}, err
+	}
+	logger, err := debugLogger()
+	if err != nil {
+		return client.Config{}, err
+	}
+	location, err := inboxLocation()
+	if err != nil {
+		return client.Config{}, err
+	}
+	return client.Config{
+		EnableDebugMode: enableDebugMode,
+		Logger:          logger,
+		HAR:             recorder(),
+		Retry:           retryPolicy,
+		RateLimit:       rateLimit,
+		Captcha:         captchaConfig(),
+		BaseURL:         baseURL,
+		SessionFile:     path,
+		Location:        location,
+	}, nil
+}
+
+// inboxLocation returns the timezone of the times displayed
+// in the inboxes, nil means the client default is used
+func inboxLocation() (*time.Location, error) {
+	if timezone == "" {
+		return nil, nil
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("--timezone is not a valid timezone : %w", err)
+	}
+	return location, nil
+}
+
+// debugLogger returns the logger writing the debug records in
+// the debug file, nil means the client default is used
+func debugLogger() (*slog.Logger, error) {
+	if debugFile == "" {
+		return nil, nil
+	}
+	if debugOutput == nil {
+		f, err := os.OpenFile(debugFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, err
+		}
+		debugOutput = f
+	}
+	return slog.New(slog.NewTextHandler(debugOutput, &slog.HandlerOptions{Level: slog.LevelDebug})), nil
+}
+
+// recorder returns the recorder of the HTTP traffic
+// when an HTTP Archive must be written
+func recorder() *client.HARRecorder {
+	if harFile != "" && harRecorder == nil {
+		harRecorder = client.NewHARRecorder(version)
+	}
+	return harRecorder
+}
+
+// captchaConfig returns the CAPTCHA wait policy,
+// the progress is reported on stderr
+func captchaConfig() client.CaptchaPolicy {
+	p := captchaPolicy
+	p.Progress = os.Stderr
+	return p
+}
+
+// sessionFilePath returns the file persisting the session
+func sessionFilePath() (string, error) {
+	if sessionFile != "" {
+		return sessionFile, nil
+	}
+	return client.DefaultSessionFile()
+}
+
+// domainsFilePath returns the file caching the
+// domains, it is stored next to the session file
+func domainsFilePath() (string, error) {
+	path, err := sessionFilePath()
+	if err != nil {
+		return "", err
 	}
+	return filepath.Join(filepath.Dir(path), "domains.json"), nil
 }
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/antham/yogo/v4/internal/client"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadEnvFlags(t *testing.T) {
+	type scenario struct {
+		name string
+		env  map[string]string
+		args []string
+		test func(time.Duration, error)
+	}
+
+	scenarios := []scenario{
+		{
+			name: "default value",
+			test: func(d time.Duration, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, time.Second, d)
+			},
+		},
+		{
+			name: "value from environment",
+			env:  map[string]string{"YOGO_RETRY_MAX_DELAY": "3s"},
+			test: func(d time.Duration, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, 3*time.Second, d)
+			},
+		},
+		{
+			name: "flag wins over environment",
+			env:  map[string]string{"YOGO_RETRY_MAX_DELAY": "3s"},
+			args: []string{"--retry-max-delay", "5s"},
+			test: func(d time.Duration, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, 5*time.Second, d)
+			},
+		},
+		{
+			name: "invalid value in environment",
+			env:  map[string]string{"YOGO_RETRY_MAX_DELAY": "whatever"},
+			test: func(d time.Duration, err error) {
+				assert.EqualError(t, err, `environment variable YOGO_RETRY_MAX_DELAY : invalid argument "whatever" for "--retry-max-delay" flag: time: invalid duration "whatever"`)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			for k, v := range scenario.env {
+				t.Setenv(k, v)
+			}
+			var d time.Duration
+			cmd := &cobra.Command{}
+			cmd.Flags().DurationVar(&d, "retry-max-delay", time.Second, "")
+			assert.NoError(t, cmd.ParseFlags(scenario.args))
+			scenario.test(d, loadEnvFlags(cmd, []string{}))
+		})
+	}
+}
+
+func TestDebugLogger(t *testing.T) {
+	logger, err := debugLogger()
+	assert.NoError(t, err)
+	assert.Nil(t, logger)
+
+	debugFile = filepath.Join(t.TempDir(), "debug.log")
+	defer func() {
+		debugOutput.Close()
+		debugOutput = nil
+		debugFile = ""
+	}()
+	for i := 0; i < 2; i++ {
+		logger, err = debugLogger()
+		assert.NoError(t, err)
+		logger.Debug("request", "id", i)
+	}
+	b, err := os.ReadFile(debugFile)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), "level=DEBUG msg=request id=0")
+	assert.Contains(t, string(b), "level=DEBUG msg=request id=1")
+}
+
+func TestRecorder(t *testing.T) {
+	assert.Nil(t, recorder())
+
+	harFile = filepath.Join(t.TempDir(), "yogo.har")
+	defer func() {
+		harFile = ""
+		harRecorder = nil
+	}()
+	r := recorder()
+	assert.NotNil(t, r)
+	assert.Same(t, r, recorder())
+}
+
+func TestCaptchaConfig(t *testing.T) {
+	captchaPolicy.Cooldown = time.Minute
+	defer func() { captchaPolicy = client.CaptchaPolicy{} }()
+	assert.Equal(t, client.CaptchaPolicy{Cooldown: time.Minute, Progress: os.Stderr}, captchaConfig())
+	assert.Nil(t, captchaPolicy.Progress)
+}
+
+func TestInboxLocation(t *testing.T) {
+	location, err := inboxLocation()
+	assert.NoError(t, err)
+	assert.Nil(t, location)
+
+	defer func() { timezone = "" }()
+	timezone = "America/New_York"
+	location, err = inboxLocation()
+	assert.NoError(t, err)
+	assert.Equal(t, "America/New_York", location.String())
+
+	timezone = "Mars/Olympus_Mons"
+	_, err = inboxLocation()
+	assert.EqualError(t, err, "--timezone is not a valid timezone : unknown time zone Mars/Olympus_Mons")
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/antham/yogo/v4/internal/client"
+	"github.com/spf13/cobra"
+)
+
+var sessionClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove the session persisted between runs",
+	RunE:  sessionClear,
+	Args:  cobra.NoArgs,
+}
+
+func sessionClear(cmd *cobra.Command, args []string) error {
+	path, err := sessionFilePath()
+	if err != nil {
+		return err
+	}
+	if err := client.ClearSessions(path); err != nil {
+		return err
+	}
+	cmd.Println(success(fmt.Sprintf(`Session "%s" successfully cleared`, path)))
+	return nil
+}
+
+func init() {
+	sessionCmd.AddCommand(sessionClearCmd)
+}
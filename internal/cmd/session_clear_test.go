@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionClear(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"sessions":{}}`), 0o600))
+	sessionFile = path
+	defer func() { sessionFile = "" }()
+
+	for i := 0; i < 2; i++ {
+		var output bytes.Buffer
+		cmd := &cobra.Command{}
+		cmd.SetOut(&output)
+		assert.NoError(t, sessionClear(cmd, []string{}))
+		assert.Equal(t, `Session "`+path+`" successfully cleared`+"\n", output.String())
+		assert.NoFileExists(t, path)
+	}
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/antham/yogo/v4/internal/client"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var sessionShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the session tokens and cookies persisted between runs",
+	RunE:  sessionShow,
+	Args:  cobra.NoArgs,
+}
+
+func sessionShow(cmd *cobra.Command, args []string) error {
+	path, err := sessionFilePath()
+	if err != nil {
+		return err
+	}
+	sessions, err := client.LoadSessions(path)
+	if err != nil {
+		return err
+	}
+
+	if dumpJSON {
+		b, err := json.Marshal(struct {
+			File     string                         `json:"file"`
+			Sessions map[string]client.SessionState `json:"sessions"`
+		}{path, sessions})
+		if err != nil {
+			return err
+		}
+		cmd.Println(string(b))
+		return nil
+	}
+
+	if len(sessions) == 0 {
+		cmd.Println(info(fmt.Sprintf(`No session stored in "%s"`, path)))
+		return nil
+	}
+	cmd.Println(renderSessions(path, sessions))
+	return nil
+}
+
+func renderSessions(path string, sessions map[string]client.SessionState) string {
+	URLs := []string{}
+	for URL := range sessions {
+		URLs = append(URLs, URL)
+	}
+	sort.Strings(URLs)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "File : %s\n", color.CyanString(path))
+	for _, URL := range URLs {
+		s := sessions[URL]
+		b.WriteString("---\n")
+		fmt.Fprintf(&b, "%s\n", color.CyanString(URL))
+		fmt.Fprintf(&b, "  Updated : %s\n", color.GreenString(s.UpdatedAt.Local().Format("2006-01-02 15:04")))
+		fmt.Fprintf(&b, "  Version : %s\n", color.YellowString(s.APIVersion))
+		fmt.Fprintf(&b, "  yp      : %s\n", color.YellowString(s.YP))
+		fmt.Fprintf(&b, "  yj      : %s\n", color.YellowString(s.YJ))
+		b.WriteString("  Cookies :")
+		if len(s.Cookies) == 0 {
+			b.WriteString(" " + color.YellowString("[no data to display]") + "\n")
+		}
+		for i, c := range s.Cookies {
+			if i > 0 {
+				b.WriteString("           ")
+			}
+			expires := "session"
+			if !c.Expires.IsZero() {
+				expires = "expires " + c.Expires.Local().Format("2006-01-02 15:04")
+			}
+			fmt.Fprintf(&b, " %s=%s (%s%s, %s)\n", c.Name, color.YellowString(c.Value), c.Domain, c.Path, expires)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func init() {
+	sessionCmd.AddCommand(sessionShowCmd)
+}
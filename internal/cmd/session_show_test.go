@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionShow(t *testing.T) {
+	type scenario struct {
+		name        string
+		content     string
+		json        bool
+		errExpected string
+		output      string
+	}
+
+	content := `{"sessions":{"https://yopmail.com":{"apiVersion":"9.0","yp":"yp1","yj":"yj1","cookies":[{"name":"compte","value":"test","domain":"yopmail.com","path":"/"}],"updatedAt":"2024-01-01T00:00:00Z"},"http://127.0.0.1:8025":{"apiVersion":"1.0","yp":"yp2","yj":"yj2","cookies":[],"updatedAt":"2024-01-01T00:00:00Z"}}}`
+	updated := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Local().Format("2006-01-02 15:04")
+
+	scenarios := []scenario{
+		{
+			name:   "No session stored",
+			output: `No session stored in "{{path}}"` + "\n",
+		},
+		{
+			name:        "Corrupted session file",
+			content:     "{",
+			errExpected: "is corrupted",
+		},
+		{
+			name:    "Sessions stored",
+			content: content,
+			output: `File : {{path}}
+---
+http://127.0.0.1:8025
+  Updated : {{updated}}
+  Version : 1.0
+  yp      : yp2
+  yj      : yj2
+  Cookies : [no data to display]
+---
+https://yopmail.com
+  Updated : {{updated}}
+  Version : 9.0
+  yp      : yp1
+  yj      : yj1
+  Cookies : compte=test (yopmail.com/, session)
+`,
+		},
+		{
+			name:    "Sessions stored as JSON",
+			content: content,
+			json:    true,
+			output:  `{"file":"{{path}}","sessions":{"http://127.0.0.1:8025":{"apiVersion":"1.0","yp":"yp2","yj":"yj2","cookies":[],"updatedAt":"2024-01-01T00:00:00Z"},"https://yopmail.com":{"apiVersion":"9.0","yp":"yp1","yj":"yj1","cookies":[{"name":"compte","value":"test","domain":"yopmail.com","path":"/"}],"updatedAt":"2024-01-01T00:00:00Z"}}}` + "\n",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "session.json")
+			if scenario.content != "" {
+				assert.NoError(t, os.WriteFile(path, []byte(scenario.content), 0o600))
+			}
+			sessionFile = path
+			dumpJSON = scenario.json
+			defer func() {
+				sessionFile = ""
+				dumpJSON = false
+			}()
+
+			var output bytes.Buffer
+			cmd := &cobra.Command{}
+			cmd.SetOut(&output)
+			err := sessionShow(cmd, []string{})
+			if scenario.errExpected != "" {
+				assert.ErrorContains(t, err, scenario.errExpected)
+				return
+			}
+			assert.NoError(t, err)
+			expected := bytes.ReplaceAll([]byte(scenario.output), []byte("{{path}}"), []byte(path))
+			expected = bytes.ReplaceAll(expected, []byte("{{updated}}"), []byte(updated))
+			assert.Equal(t, string(expected), output.String())
+		})
+	}
+}
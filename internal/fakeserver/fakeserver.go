@@ -0,0 +1,409 @@
+package fakeserver
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/antham/yogo/v4/internal/timezone"
+	"github.com/jaytaylor/html2text"
+)
+
+const apiVersion = "9.0"
+const itemNumber = 15
+
+// domains are the alias domains listed by the server
+var domains = []string{"yopmail.com", "yopmail.fr", "yopmail.net"}
+
+// Mail is a message stored in the fake server
+type Mail struct {
+	ID       string    `json:"id"`
+	FromName string    `json:"fromName,omitempty"`
+	From     string    `json:"from"`
+	To       string    `json:"to"`
+	Subject  string    `json:"subject"`
+	Body     string    `json:"body"`
+	Date     time.Time `json:"date"`
+	IsSPAM   bool      `json:"isSPAM"`
+}
+
+// Server emulates the yopmail endpoints used by yogo
+type Server struct {
+	mu           sync.Mutex
+	inboxes      map[string][]Mail
+	sequence     int
+	yp           string
+	yj           string
+	captcha      bool
+	captchaAfter int
+	requests     int
+	now          func() time.Time
+}
+
+// New creates a new fake server
+func New() *Server {
+	s := &Server{
+		inboxes: map[string][]Mail{},
+		now:     time.Now,
+	}
+	s.RotateTokens()
+	return s
+}
+
+// inboxName returns the key of an inbox, yopmail
+// inbox names are case insensitive
+func inboxName(inbox string) string {
+	return strings.ToLower(strings.TrimSpace(inbox))
+}
+
+// AddMail stores a mail at the top of an inbox
+func (s *Server) AddMail(inbox string, m Mail) Mail {
+	inbox = inboxName(inbox)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sequence++
+	m.ID = "e_" + base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("yogo-fake-%08d", s.sequence)))
+	if m.To == "" {
+		m.To = inbox + "@" + domains[0]
+	}
+	if m.Date.IsZero() {
+		m.Date = s.now()
+	}
+	s.inboxes[inbox] = append([]Mail{m}, s.inboxes[inbox]...)
+	return m
+}
+
+// Mails returns the mails of an inbox, the most recent first
+func (s *Server) Mails(inbox string) []Mail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Mail{}, s.inboxes[inboxName(inbox)]...)
+}
+
+// SetCAPTCHA enables or disables the CAPTCHA, when after is greater
+// than 0 the CAPTCHA is enabled once this number of requests
+// have been served
+func (s *Server) SetCAPTCHA(enabled bool, after int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.captcha = enabled
+	s.captchaAfter = after
+	s.requests = 0
+}
+
+// RotateTokens renews the yp and yj tokens, the tokens
+// previously served are rejected from now
+func (s *Server) RotateTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sequence++
+	s.yp = fmt.Sprintf("yp%08d", s.sequence)
+	s.yj = fmt.Sprintf("yj%08d", s.sequence)
+}
+
+// Handler returns the HTTP handler serving the yopmail
+// endpoints and the admin API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.home)
+	mux.HandleFunc("GET /ver/{version}/webmail.js", s.webmail)
+	mux.HandleFunc("GET /en/inbox", s.inbox)
+	mux.HandleFunc("GET /en/mail", s.mail)
+	mux.HandleFunc("POST /en/writepost", s.writePost)
+	mux.HandleFunc("GET /en/domain", s.domains)
+	mux.HandleFunc("GET /admin/inboxes/{inbox}/mails", s.adminListMails)
+	mux.HandleFunc("POST /admin/inboxes/{inbox}/mails", s.adminAddMail)
+	mux.HandleFunc("DELETE /admin/inboxes/{inbox}/mails", s.adminFlushMails)
+	mux.HandleFunc("PUT /admin/captcha", s.adminCAPTCHA)
+	mux.HandleFunc("POST /admin/tokens/rotate", s.adminRotateTokens)
+	return mux
+}
+
+func (s *Server) home(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	yp := s.yp
+	s.mu.Unlock()
+	render(w, homeTemplate, map[string]string{"Version": apiVersion, "YP": yp})
+}
+
+func (s *Server) webmail(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	yj := s.yj
+	s.mu.Unlock()
+	w.Header().Set("Content-Type", "application/javascript")
+	fmt.Fprintf(w, "function r(){var u='inbox?login='+l+'&p='+p+'&d=&ctrl=&yp='+yp+'&yj=%s&v=%s';}", yj, apiVersion)
+}
+
+func (s *Server) inbox(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.blocked() || q.Get("yp") != s.yp || q.Get("yj") != s.yj || q.Get("v") != apiVersion {
+		render(w, captchaTemplate, nil)
+		return
+	}
+	login := inboxName(q.Get("login"))
+	switch d := q.Get("d"); d {
+	case "":
+	case "all":
+		delete(s.inboxes, login)
+	default:
+		mails := []Mail{}
+		for _, m := range s.inboxes[login] {
+			if m.ID != d {
+				mails = append(mails, m)
+			}
+		}
+		s.inboxes[login] = mails
+	}
+	page, err := strconv.Atoi(q.Get("p"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	mails := s.inboxes[login]
+	start := min((page-1)*itemNumber, len(mails))
+	end := min(start+itemNumber, len(mails))
+	render(w, inboxTemplate, map[string]any{
+		"Entries":  s.inboxEntries(mails[start:end]),
+		"Finrmail": template.JS(fmt.Sprintf("w.finrmail(%d, %d, 0, 0, 0, 'alt.fake', '')", len(mails), page)),
+	})
+}
+
+// inboxEntry is a line of an inbox page, either a day separator or a mail
+type inboxEntry struct {
+	Mail
+	Day  string
+	Time string
+}
+
+// inboxEntries lists the mails of an inbox page, every page starts
+// with a day separator and a new one is inserted when the day changes,
+// the dates are displayed in the yopmail timezone
+func (s *Server) inboxEntries(mails []Mail) []inboxEntry {
+	location := timezone.Yopmail()
+	today := s.now().In(location)
+	entries := []inboxEntry{}
+	day := ""
+	for _, m := range mails {
+		date := m.Date.In(location)
+		if d := dayLabel(date, today); d != day {
+			day = d
+			entries = append(entries, inboxEntry{Day: day})
+		}
+		entries = append(entries, inboxEntry{Mail: m, Time: date.Format("15:04")})
+	}
+	return entries
+}
+
+// frenchWeekdays are the weekday names displayed by yopmail
+var frenchWeekdays = []string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"}
+
+// dayLabel returns the day separator of a date as displayed by yopmail
+func dayLabel(date time.Time, today time.Time) string {
+	// the days are compared in UTC to not be fooled by daylight saving time
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC).Sub(day).Hours() / 24)
+	switch {
+	case days == 0:
+		return "aujourd'hui"
+	case days > 0 && days < 7:
+		return frenchWeekdays[date.Weekday()]
+	}
+	return date.Format("02/01/2006")
+}
+
+func (s *Server) mail(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.blocked() {
+		render(w, mailCaptchaTemplate, nil)
+		return
+	}
+	ID := q.Get("id")
+	if len(ID) < 2 {
+		http.NotFound(w, r)
+		return
+	}
+	for _, m := range s.inboxes[inboxName(q.Get("b"))] {
+		if m.ID != ID[1:] {
+			continue
+		}
+		switch ID[:1] {
+		case "m":
+			render(w, htmlMailTemplate, htmlMail(m))
+		case "t":
+			render(w, textMailTemplate, textMail(m))
+		case "s":
+			render(w, sourceMailTemplate, source(m))
+		default:
+			http.NotFound(w, r)
+		}
+		return
+	}
+	http.NotFound(w, r)
+}
+
+// writePost delivers a mail sent from an inbox, only the
+// addresses on the listed domains are accepted as recipient
+func (s *Server) writePost(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	s.mu.Lock()
+	blocked := s.blocked() || q.Get("yp") != s.yp || q.Get("yj") != s.yj || q.Get("v") != apiVersion
+	s.mu.Unlock()
+	if blocked {
+		render(w, mailCaptchaTemplate, nil)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	to := strings.ToLower(r.PostForm.Get("msgto"))
+	inbox, domain, _ := strings.Cut(to, "@")
+	if inbox == "" || !slices.Contains(domains, domain) {
+		http.Error(w, fmt.Sprintf("recipient %q is not a yopmail address", to), http.StatusBadRequest)
+		return
+	}
+	s.AddMail(inbox, Mail{
+		From:    r.PostForm.Get("msgfrom"),
+		To:      to,
+		Subject: r.PostForm.Get("msgsubject"),
+		Body:    strings.ReplaceAll(template.HTMLEscapeString(r.PostForm.Get("msgbody")), "\n", "<br>"),
+	})
+	render(w, sentTemplate, nil)
+}
+
+func (s *Server) domains(w http.ResponseWriter, r *http.Request) {
+	render(w, domainsTemplate, domains)
+}
+
+func (s *Server) adminListMails(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.Mails(r.PathValue("inbox")))
+}
+
+func (s *Server) adminAddMail(w http.ResponseWriter, r *http.Request) {
+	var m Mail
+	if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusCreated, s.AddMail(r.PathValue("inbox"), m))
+}
+
+func (s *Server) adminFlushMails(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	delete(s.inboxes, inboxName(r.PathValue("inbox")))
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) adminCAPTCHA(w http.ResponseWriter, r *http.Request) {
+	var c struct {
+		Enabled bool `json:"enabled"`
+		After   int  `json:"after"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	s.SetCAPTCHA(c.Enabled, c.After)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) adminRotateTokens(w http.ResponseWriter, r *http.Request) {
+	s.RotateTokens()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// blocked counts the request and tells if the CAPTCHA
+// must be served, the lock must be held
+func (s *Server) blocked() bool {
+	s.requests++
+	if s.captchaAfter > 0 && s.requests > s.captchaAfter {
+		s.captcha = true
+	}
+	return s.captcha
+}
+
+func htmlMail(m Mail) map[string]any {
+	from := fmt.Sprintf("<%s>", m.From)
+	if m.FromName != "" {
+		from = fmt.Sprintf("%s <%s>", m.FromName, m.From)
+	}
+	return map[string]any{
+		"Subject": m.Subject,
+		"From":    from,
+		"Date":    m.Date.Format("Monday, January 02, 2006 3:04:05 PM"),
+		"Body":    template.HTML(m.Body),
+	}
+}
+
+// textMail renders the body as plain text, the
+// line breaks of the text are kept with br tags
+func textMail(m Mail) map[string]any {
+	data := htmlMail(m)
+	text, err := html2text.FromString(m.Body)
+	if err != nil {
+		text = m.Body
+	}
+	data["Body"] = template.HTML(strings.ReplaceAll(template.HTMLEscapeString(text), "\n", "<br>"))
+	return data
+}
+
+func source(m Mail) map[string]any {
+	from := m.From
+	if m.FromName != "" {
+		from = fmt.Sprintf("%s <%s>", m.FromName, m.From)
+	}
+	headers := []string{
+		"From: " + from,
+		"To: " + m.To,
+		"Subject: " + m.Subject,
+		"Date: " + m.Date.Format(time.RFC1123Z),
+		"Message-ID: <" + strings.TrimRight(m.ID, "=") + "@yogo.fake>",
+		"MIME-Version: 1.0",
+		"Content-Type: text/html; charset=utf-8",
+	}
+	return map[string]any{"Source": strings.Join(headers, "\n") + "\n\n" + m.Body}
+}
+
+func render(w http.ResponseWriter, tpl *template.Template, data any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := tpl.Execute(w, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+var homeTemplate = template.Must(template.New("home").Parse(`<!DOCTYPE html><html><head><title>YOPmail</title><script src="/ver/{{.Version}}/webmail.js"></script></head><body><form><input type="hidden" name="yp" id="yp" value="{{.YP}}"><input type="text" id="login" name="login"></form></body></html>`))
+
+var inboxTemplate = template.Must(template.New("inbox").Parse(`<!DOCTYPE html><html><head><title>Inbox</title></head><body class="bodyinbox"><div class="mctn">
+{{- range .Entries }}{{ if .Day }}<div class="mday">{{ .Day }}</div>{{ else }}<div class="m" id="{{ .ID }}"><button class="lm"><div class="lmfd"><span class="lmh">{{ .Time }}</span><span class="lmf">{{ if .IsSPAM }}[SPAM]{{ end }}{{ if .FromName }}{{ .FromName }}{{ else }}{{ .From }}{{ end }}</span></div><div class="lms">{{ .Subject }}</div></button></div>{{ end }}{{ end -}}
+<script>{ {{ .Finrmail }}; }</script></div></body></html>`))
+
+var captchaTemplate = template.Must(template.New("captcha").Parse(`<!DOCTYPE html><html><head><title>Inbox</title></head><body><div class="g-recaptcha"></div></body></html>`))
+
+var mailCaptchaTemplate = template.Must(template.New("mailCaptcha").Parse(`<!DOCTYPE html><html><head><title>Mail</title></head><body><script>window.showRc()</script></body></html>`))
+
+var domainsTemplate = template.Must(template.New("domains").Parse(`<!DOCTYPE html><html><head><title>Domains</title></head><body><select id="domain">{{ range . }}<option>@{{ . }}</option>{{ end }}</select></body></html>`))
+
+var sentTemplate = template.Must(template.New("sent").Parse(`<!DOCTYPE html><html><head><title>Write</title></head><body><div id="msgsent">Your message has been sent</div></body></html>`))
+
+var htmlMailTemplate = template.Must(template.New("htmlMail").Parse(`<!DOCTYPE html><html><head><title>Mail</title></head><body><header><div class="fl"><div class="ellipsis nw b f18">{{ .Subject }}</div><div class="md text zoom nw f24"><span class="ellipsis b">{{ .From }}</span></div><div class="md text zoom nw f24"><span class="ellipsis">{{ .Date }}</span></div></div></header><main><div id="mailctn"><div id="mail">{{ .Body }}</div></div></main></body></html>`))
+
+var textMailTemplate = template.Must(template.New("textMail").Parse(`<!DOCTYPE html><html><head><title>Mail</title></head><body><header><div class="fl"><div class="ellipsis nw b f18">{{ .Subject }}</div><div class="md text zoom nw f24"><span class="ellipsis b">{{ .From }}</span></div><div class="md text zoom nw f24"><span class="ellipsis">{{ .Date }}</span></div></div></header><main><div id="mailctn"><div id="mail">{{ .Body }}</div></div></main></body></html>`))
+
+var sourceMailTemplate = template.Must(template.New("sourceMail").Parse(`<!DOCTYPE html><html><head><title>Mail</title></head><body><main><div id="mailctn"><div id="mail"><pre>{{ .Source }}</pre></div></div></main></body></html>`))
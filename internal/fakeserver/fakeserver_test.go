@@ -0,0 +1,242 @@
+package fakeserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antham/yogo/v4/internal/client"
+	"github.com/antham/yogo/v4/internal/inbox"
+	"github.com/antham/yogo/v4/internal/timezone"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer(t *testing.T) {
+	server := New()
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	date := time.Date(2023, 8, 13, 22, 45, 9, 0, time.UTC)
+	for i := 0; i < 17; i++ {
+		server.AddMail("test", Mail{From: fmt.Sprintf("sender%d@example.com", i), Subject: fmt.Sprintf("subject %d", i), Body: "<p>Hello</p>", Date: date})
+	}
+	server.AddMail("test", Mail{FromName: "John", From: "john@example.com", Subject: "Welcome", Body: `<p>Hello <a href="https://example.com">John</a></p>`, Date: date, IsSPAM: true})
+
+	ctx := context.Background()
+	config := client.Config{BaseURL: ts.URL}
+	in, err := inbox.NewInbox[client.MailHTMLDoc](ctx, "test", config)
+	assert.NoError(t, err)
+	assert.NoError(t, in.ParseInboxPages(ctx, 20))
+	assert.Equal(t, 18, in.Count())
+	item := in.GetMails()[0]
+	assert.True(t, date.Truncate(time.Minute).Equal(*item.Date))
+	assert.Equal(t, timezone.Yopmail(), item.Date.Location())
+	item.Date = nil
+	assert.Equal(t, inbox.InboxItem{ID: server.Mails("test")[0].ID, Sender: &inbox.Sender{Name: "John"}, Subject: "Welcome", IsSPAM: true}, item)
+	assert.Equal(t, "subject 0", in.GetMails()[17].Subject)
+
+	r, err := in.Fetch(ctx, 0)
+	assert.NoError(t, err)
+	m := r.(*inbox.HTMLMail)
+	assert.Equal(t, "Welcome", m.Subject)
+	assert.Equal(t, "John", m.Sender.Name)
+	assert.Equal(t, "john@example.com", m.Sender.Mail)
+	assert.Equal(t, date, *m.Date)
+	assert.Equal(t, "Hello John ( https://example.com )", m.Body)
+
+	text, err := inbox.NewInbox[client.MailTextDoc](ctx, "test", config)
+	assert.NoError(t, err)
+	assert.NoError(t, text.ParseInboxPages(ctx, 1))
+	r, err = text.Fetch(ctx, 0)
+	assert.NoError(t, err)
+	tm := r.(*inbox.TextMail)
+	assert.Equal(t, "Welcome", tm.Subject)
+	assert.Equal(t, "john@example.com", tm.Sender.Mail)
+	assert.Equal(t, "Hello John ( https://example.com )", tm.Body)
+
+	source, err := inbox.NewInbox[client.MailSourceDoc](ctx, "test", config)
+	assert.NoError(t, err)
+	assert.NoError(t, source.ParseInboxPages(ctx, 1))
+	r, err = source.Fetch(ctx, 0)
+	assert.NoError(t, err)
+	s := r.(*inbox.SourceMail)
+	assert.Equal(t, []string{"John <john@example.com>"}, s.Headers["From"])
+	assert.Equal(t, []string{"test@yopmail.com"}, s.Headers["To"])
+	assert.Equal(t, `<p>Hello <a href="https://example.com">John</a></p>`, s.Body)
+
+	assert.NoError(t, in.Delete(ctx, 0))
+	assert.Len(t, server.Mails("test"), 17)
+
+	server.RotateTokens()
+	assert.NoError(t, in.Flush(ctx))
+	assert.Empty(t, server.Mails("test"))
+}
+
+func TestServerCAPTCHA(t *testing.T) {
+	server := New()
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+	server.AddMail("test", Mail{From: "john@example.com", Subject: "Welcome"})
+
+	ctx := context.Background()
+	in, err := inbox.NewInbox[client.MailHTMLDoc](ctx, "test", client.Config{BaseURL: ts.URL})
+	assert.NoError(t, err)
+	server.SetCAPTCHA(false, 1)
+	assert.NoError(t, in.ParseInboxPages(ctx, 1))
+	_, err = in.Fetch(ctx, 0)
+	assert.ErrorIs(t, err, client.ErrCaptcha)
+	assert.ErrorIs(t, in.ParseInboxPages(ctx, 1), client.ErrCaptcha)
+
+	server.SetCAPTCHA(false, 0)
+	_, err = in.Fetch(ctx, 0)
+	assert.NoError(t, err)
+}
+
+func TestServerSend(t *testing.T) {
+	server := New()
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	ctx := context.Background()
+	in, err := inbox.NewInbox[client.MailHTMLDoc](ctx, "test", client.Config{BaseURL: ts.URL})
+	assert.NoError(t, err)
+	assert.NoError(t, in.Send(ctx, "test@yopmail.com", "Other@yopmail.com", "Hi", "<b>Hello</b>\nBye"))
+	mails := server.Mails("other")
+	assert.Len(t, mails, 1)
+	assert.Equal(t, "test@yopmail.com", mails[0].From)
+	assert.Equal(t, "other@yopmail.com", mails[0].To)
+	assert.Equal(t, "Hi", mails[0].Subject)
+	assert.Equal(t, "&lt;b&gt;Hello&lt;/b&gt;<br>Bye", mails[0].Body)
+
+	assert.NoError(t, in.Send(ctx, "test@yopmail.net", "other@yopmail.fr", "Hi", "Hello"))
+	mails = server.Mails("other")
+	assert.Len(t, mails, 2)
+	assert.Equal(t, "test@yopmail.net", mails[0].From)
+	assert.Equal(t, "other@yopmail.fr", mails[0].To)
+
+	var httpErr *client.HTTPError
+	assert.ErrorAs(t, in.Send(ctx, "test@yopmail.com", "other@example.com", "Hi", "Hello"), &httpErr)
+	assert.Equal(t, http.StatusBadRequest, httpErr.StatusCode)
+
+	server.SetCAPTCHA(true, 0)
+	assert.ErrorIs(t, in.Send(ctx, "test@yopmail.com", "other@yopmail.com", "Hi", "Hello"), client.ErrCaptcha)
+}
+
+func TestServerDomains(t *testing.T) {
+	server := New()
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	ctx := context.Background()
+	c, err := client.New[client.MailHTMLDoc](ctx, client.Config{BaseURL: ts.URL})
+	assert.NoError(t, err)
+	domains, err := c.GetDomains(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"yopmail.com", "yopmail.fr", "yopmail.net"}, domains)
+}
+
+func TestServerAdminAPI(t *testing.T) {
+	server := New()
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	res, err := http.Post(ts.URL+"/admin/inboxes/Test/mails", "application/json", bytes.NewBufferString(`{"from":"john@example.com","subject":"Welcome","body":"Hello"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+	assert.Len(t, server.Mails("test"), 1)
+	assert.Equal(t, "test@yopmail.com", server.Mails("test")[0].To)
+
+	res, err = http.Post(ts.URL+"/admin/inboxes/test/mails", "application/json", bytes.NewBufferString(`{`))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+	res, err = http.Get(ts.URL + "/admin/inboxes/TEST/mails")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	var mails []Mail
+	assert.NoError(t, json.NewDecoder(res.Body).Decode(&mails))
+	assert.Len(t, mails, 1)
+	assert.Len(t, server.Mails("Test"), 1)
+
+	req, err := http.NewRequest(http.MethodPut, ts.URL+"/admin/captcha", bytes.NewBufferString(`{"enabled":true}`))
+	assert.NoError(t, err)
+	res, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+	assert.True(t, server.captcha)
+
+	res, err = http.Post(ts.URL+"/admin/tokens/rotate", "application/json", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+
+	req, err = http.NewRequest(http.MethodDelete, ts.URL+"/admin/inboxes/test/mails", nil)
+	assert.NoError(t, err)
+	res, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+	assert.Empty(t, server.Mails("test"))
+}
+
+func TestDayLabel(t *testing.T) {
+	type scenario struct {
+		name     string
+		date     time.Time
+		expected string
+	}
+
+	paris := timezone.Yopmail()
+	// the clocks were set forward the day before, it lasted 23 hours
+	today := time.Date(2023, 3, 27, 0, 30, 0, 0, paris)
+
+	scenarios := []scenario{
+		{"today", time.Date(2023, 3, 27, 0, 10, 0, 0, paris), "aujourd'hui"},
+		{"yesterday across daylight saving time", time.Date(2023, 3, 26, 23, 0, 0, 0, paris), "dimanche"},
+		{"six days ago", time.Date(2023, 3, 21, 12, 0, 0, 0, paris), "mardi"},
+		{"a week ago", time.Date(2023, 3, 20, 12, 0, 0, 0, paris), "20/03/2023"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			assert.Equal(t, s.expected, dayLabel(s.date, today))
+		})
+	}
+}
+
+// progressFunc calls a function on every progress line
+type progressFunc func(string)
+
+func (f progressFunc) Write(p []byte) (int, error) {
+	f(string(p))
+	return len(p), nil
+}
+
+func TestServerCAPTCHAWaitWithRotatedTokens(t *testing.T) {
+	server := New()
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+	server.AddMail("test", Mail{From: "john@example.com", Subject: "Welcome"})
+
+	ctx := context.Background()
+	// the CAPTCHA is lifted while the wait starts but
+	// only the requests with fresh tokens get through
+	progress := progressFunc(func(line string) {
+		if strings.HasPrefix(line, "CAPTCHA detected") {
+			server.SetCAPTCHA(false, 0)
+			server.RotateTokens()
+		}
+	})
+	in, err := inbox.NewInbox[client.MailHTMLDoc](ctx, "test", client.Config{
+		BaseURL: ts.URL,
+		Captcha: client.CaptchaPolicy{Cooldown: time.Millisecond, Deadline: time.Second, Progress: progress},
+	})
+	assert.NoError(t, err)
+	server.SetCAPTCHA(true, 0)
+	assert.NoError(t, in.ParseInboxPages(ctx, 1))
+	assert.Equal(t, 1, in.Count())
+}
@@ -0,0 +1,140 @@
+package inbox
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// weekdays maps the weekday names used by the
+// inbox day separators, in French and English
+var weekdays = map[string]time.Weekday{
+	"dimanche": time.Sunday, "sunday": time.Sunday,
+	"lundi": time.Monday, "monday": time.Monday,
+	"mardi": time.Tuesday, "tuesday": time.Tuesday,
+	"mercredi": time.Wednesday, "wednesday": time.Wednesday,
+	"jeudi": time.Thursday, "thursday": time.Thursday,
+	"vendredi": time.Friday, "friday": time.Friday,
+	"samedi": time.Saturday, "saturday": time.Saturday,
+}
+
+// months maps the month names used by the inbox day
+// separators, in French, with and without accents, and English
+var months = map[string]time.Month{
+	"janvier": time.January, "january": time.January,
+	"février": time.February, "fevrier": time.February, "february": time.February,
+	"mars": time.March, "march": time.March,
+	"avril": time.April, "april": time.April,
+	"mai": time.May, "may": time.May,
+	"juin": time.June, "june": time.June,
+	"juillet": time.July, "july": time.July,
+	"août": time.August, "aout": time.August, "august": time.August,
+	"septembre": time.September, "september": time.September,
+	"octobre": time.October, "october": time.October,
+	"novembre": time.November, "november": time.November,
+	"décembre": time.December, "decembre": time.December, "december": time.December,
+}
+
+// startOfDay returns the midnight of the day of a time
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// parseDay returns the midnight of the day described by an inbox day
+// separator: "aujourd'hui", "hier", a weekday name for the last seven
+// days or a date ("13/08/2023", "2023-08-13", "dimanche 13 août 2023",
+// "August 13"), a date without year is the most recent one
+func parseDay(s string, now time.Time) (time.Time, bool) {
+	today := startOfDay(now)
+	s = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(s), "’", "'"))
+	switch s {
+	case "aujourd'hui", "today":
+		return today, true
+	case "hier", "yesterday":
+		return today.AddDate(0, 0, -1), true
+	}
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || r == ',' || r == '.'
+	})
+	if len(fields) == 0 {
+		return time.Time{}, false
+	}
+	if weekday, ok := weekdays[fields[0]]; ok {
+		if len(fields) == 1 {
+			for i := 1; i <= 7; i++ {
+				if day := today.AddDate(0, 0, -i); day.Weekday() == weekday {
+					return day, true
+				}
+			}
+		}
+		fields = fields[1:]
+	}
+
+	var day, year int
+	var month time.Month
+	if len(fields) == 1 {
+		parts := strings.FieldsFunc(fields[0], func(r rune) bool { return r == '/' || r == '-' })
+		numbers := make([]int, len(parts))
+		for i, p := range parts {
+			n, err := strconv.Atoi(p)
+			if err != nil {
+				return time.Time{}, false
+			}
+			numbers[i] = n
+		}
+		switch {
+		case len(parts) == 3 && len(parts[0]) == 4:
+			year, month, day = numbers[0], time.Month(numbers[1]), numbers[2]
+		case len(parts) == 3:
+			day, month, year = numbers[0], time.Month(numbers[1]), numbers[2]
+		case len(parts) == 2:
+			day, month = numbers[0], time.Month(numbers[1])
+		default:
+			return time.Time{}, false
+		}
+	} else {
+		for _, f := range fields {
+			if m, ok := months[f]; ok {
+				month = m
+				continue
+			}
+			n, err := strconv.Atoi(f)
+			switch {
+			case err != nil:
+				return time.Time{}, false
+			case len(f) == 4:
+				year = n
+			default:
+				day = n
+			}
+		}
+	}
+	if year > 0 && year < 100 {
+		year += 2000
+	}
+
+	guessYear := year == 0
+	if guessYear {
+		year = today.Year()
+	}
+	date := time.Date(year, month, day, 0, 0, 0, 0, today.Location())
+	if date.Day() != day || date.Month() != month {
+		return time.Time{}, false
+	}
+	if guessYear && date.After(today) {
+		date = date.AddDate(-1, 0, 0)
+	}
+	return date, true
+}
+
+// parseTime returns the time of a day displayed
+// next to a mail in an inbox, "23:14" for instance
+func parseTime(day time.Time, s string) *time.Time {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return nil
+	}
+	date := time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, day.Location())
+	return &date
+}
@@ -0,0 +1,60 @@
+package inbox
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDay(t *testing.T) {
+	type scenario struct {
+		name     string
+		day      string
+		expected time.Time
+		ok       bool
+	}
+
+	paris, err := time.LoadLocation("Europe/Paris")
+	assert.NoError(t, err)
+	// a wednesday
+	now := time.Date(2023, 8, 16, 10, 30, 0, 0, paris)
+	day := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, paris)
+	}
+
+	scenarios := []scenario{
+		{"today", "aujourd'hui", day(2023, 8, 16), true},
+		{"today with a typographic apostrophe", " Aujourd’hui ", day(2023, 8, 16), true},
+		{"today in English", "Today", day(2023, 8, 16), true},
+		{"yesterday", "hier", day(2023, 8, 15), true},
+		{"weekday", "lundi", day(2023, 8, 14), true},
+		{"same weekday as today", "mercredi", day(2023, 8, 9), true},
+		{"weekday in English", "Sunday", day(2023, 8, 13), true},
+		{"date with slashes", "13/08/2023", day(2023, 8, 13), true},
+		{"date with a short year", "13/08/23", day(2023, 8, 13), true},
+		{"ISO date", "2023-08-13", day(2023, 8, 13), true},
+		{"day and month", "13/08", day(2023, 8, 13), true},
+		{"day and month of the previous year", "20/12", day(2022, 12, 20), true},
+		{"long French date", "dimanche 13 août 2023", day(2023, 8, 13), true},
+		{"French date without year", "13 aout", day(2023, 8, 13), true},
+		{"long English date", "Sunday, August 13, 2023", day(2023, 8, 13), true},
+		{"invalid date", "31/02/2023", time.Time{}, false},
+		{"unknown day", "demain", time.Time{}, false},
+		{"empty", "", time.Time{}, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			d, ok := parseDay(s.day, now)
+			assert.Equal(t, s.ok, ok)
+			assert.Equal(t, s.expected, d)
+		})
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	day := time.Date(2023, 8, 13, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2023, 8, 13, 23, 14, 0, 0, time.UTC), *parseTime(day, " 23:14 "))
+	assert.Nil(t, parseTime(day, "11pm"))
+}
@@ -0,0 +1,19 @@
+package inbox
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrEmptyInbox is returned when an inbox contains no mail
+var ErrEmptyInbox = errors.New("inbox is empty")
+
+// OffsetError is returned when an offset doesn't target a mail of an inbox
+type OffsetError struct {
+	Offset string
+	Reason string
+}
+
+func (e *OffsetError) Error() string {
+	return fmt.Sprintf(`offset "%s" %s`, e.Offset, e.Reason)
+}
@@ -0,0 +1,76 @@
+package inbox
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/antham/yogo/v4/internal/inbox/internal/mail"
+)
+
+const minCodeLength = 4
+const maxCodeLength = 8
+
+// Extractable is a mail values can be extracted from
+type Extractable interface {
+	Text() string
+	URLs() []string
+}
+
+// ExtractLinks returns the links of a mail in the order they appear
+func ExtractLinks(m Extractable) []string {
+	return m.URLs()
+}
+
+// ExtractCodes returns the numbers made of 4 to 8 digits standing on
+// their own in the text of a mail, like verification codes, the URLs
+// and the years are ignored
+func ExtractCodes(m Extractable) []string {
+	codes := []string{}
+	text := m.Text()
+	for _, u := range mail.FindURLs(text) {
+		text = strings.ReplaceAll(text, u, " ")
+	}
+	// a code may directly follow a colon, "code:123456" for instance
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return unicode.IsSpace(r) || r == ':'
+	})
+	for _, field := range fields {
+		field = strings.TrimFunc(field, unicode.IsPunct)
+		if len(field) < minCodeLength || len(field) > maxCodeLength || isYear(field) {
+			continue
+		}
+		if strings.IndexFunc(field, func(r rune) bool { return r < '0' || r > '9' }) == -1 {
+			codes = append(codes, field)
+		}
+	}
+	return mail.Dedup(codes)
+}
+
+// isYear tells if a number looks like a year, the copyright
+// notices of the footers are not verification codes
+func isYear(s string) bool {
+	year, err := strconv.Atoi(s)
+	return err == nil && len(s) == 4 && year >= 1900 && year <= 2099
+}
+
+// ExtractMatches returns the matches of a regular expression in
+// the text and the links of a mail, the first group of the
+// expression is returned instead of the whole match when defined,
+// the matches where the group didn't participate are skipped
+func ExtractMatches(m Extractable, re *regexp.Regexp) []string {
+	matches := []string{}
+	for _, s := range append([]string{m.Text()}, m.URLs()...) {
+		for _, match := range re.FindAllStringSubmatchIndex(s, -1) {
+			if len(match) > 2 {
+				match = match[2:]
+			}
+			if match[0] < 0 {
+				continue
+			}
+			matches = append(matches, s[match[0]:match[1]])
+		}
+	}
+	return mail.Dedup(matches)
+}
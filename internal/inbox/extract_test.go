@@ -0,0 +1,35 @@
+package inbox
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type extractableMock struct {
+	text string
+	urls []string
+}
+
+func (e extractableMock) Text() string {
+	return e.text
+}
+
+func (e extractableMock) URLs() []string {
+	return e.urls
+}
+
+func TestExtract(t *testing.T) {
+	m := extractableMock{
+		text: "Your code: 123456. It expires in 10 minutes (2024-03-01), use 123456 or go to https://example.com/1234567?otp=98765\nRef. #87654321, order 123456789, backup code:24680\n© 2024 Example",
+		urls: []string{"https://example.com/1234567?otp=98765", "https://example.com/help"},
+	}
+
+	assert.Equal(t, []string{"https://example.com/1234567?otp=98765", "https://example.com/help"}, ExtractLinks(m))
+	assert.Equal(t, []string{"123456", "87654321", "24680"}, ExtractCodes(m))
+	assert.Equal(t, []string{"98765"}, ExtractMatches(m, regexp.MustCompile(`otp=(\d+)`)))
+	assert.Equal(t, []string{"https://example.com/1234567", "https://example.com/help"}, ExtractMatches(m, regexp.MustCompile(`https://example\.com/\w+`)))
+	assert.Empty(t, ExtractMatches(m, regexp.MustCompile(`nothing`)))
+	assert.Equal(t, []string{"98765"}, ExtractMatches(m, regexp.MustCompile(`(?:otp=(\d+))|expires`)))
+}
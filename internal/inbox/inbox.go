@@ -2,8 +2,10 @@ package inbox
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"regexp"
 	"strconv"
 	"strings"
 	"text/template"
@@ -20,14 +22,38 @@ type Render interface {
 	JSON() (string, error)
 }
 
+// HTMLMail is the mail returned by Fetch on an HTML inbox
+type HTMLMail = mail.HTMLMail
+
+// TextMail is the mail returned by Fetch on a text inbox
+type TextMail = mail.TextMail
+
+// SourceMail is the mail returned by Fetch on a source inbox
+type SourceMail = mail.SourceMail
+
+// Part is a node of the MIME tree of a SourceMail
+type Part = mail.Part
+
+// Attachment is a file attached to a SourceMail
+type Attachment = mail.Attachment
+
+// Address is a parsed address of a SourceMail header
+type Address = mail.Address
+
+// MailSender is the sender of an HTMLMail
+type MailSender = mail.Sender
+
 const noDataToDisplayMsg = "[no data to display]"
 const itemNumber = 15
 
+var mailTotalRegexp = regexp.MustCompile(`w\.finrmail\(\s*(\d+)\s*,`)
+
 // Inbox represents a mail collection
 type Inbox[M client.MailDoc] struct {
 	Name       string      `json:"name"`
 	InboxItems []InboxItem `json:"mails"`
 	client     client.Client[M]
+	now        func() time.Time
 }
 
 type Sender struct {
@@ -46,20 +72,30 @@ type InboxItem struct {
 }
 
 // NewInbox creates a new mail inbox
-func NewInbox[M client.MailDoc](name string, enableDebugMode bool) (*Inbox[M], error) {
-	client, err := client.New[M](enableDebugMode)
+func NewInbox[M client.MailDoc](ctx context.Context, name string, config client.Config) (*Inbox[M], error) {
+	client, err := client.New[M](ctx, config)
+	return NewInboxWithClient(name, client), err
+}
+
+// NewInboxWithClient creates a new mail inbox using an existing client
+func NewInboxWithClient[M client.MailDoc](name string, client client.Client[M]) *Inbox[M] {
 	return &Inbox[M]{
 		client:     client,
 		Name:       name,
 		InboxItems: []InboxItem{},
-	}, err
+		now:        time.Now,
+	}
 }
 
 // Fetch retrieves the full email content from the given
 // inbox email offset
-func (i *Inbox[M]) Fetch(offset int) (Render, error) {
-	ID := &i.InboxItems[offset].ID
-	doc, err := i.client.GetMailPage(i.Name, *ID)
+func (i *Inbox[M]) Fetch(ctx context.Context, offset int) (Render, error) {
+	return i.FetchByID(ctx, i.InboxItems[offset].ID)
+}
+
+// FetchByID retrieves the full email content from its identifier
+func (i *Inbox[M]) FetchByID(ctx context.Context, ID string) (Render, error) {
+	doc, err := i.client.GetMailPage(ctx, i.Name, ID)
 	if err != nil {
 		return nil, err
 	}
@@ -67,7 +103,7 @@ func (i *Inbox[M]) Fetch(offset int) (Render, error) {
 	if err != nil {
 		return nil, err
 	}
-	m.SetID(*ID)
+	m.SetID(ID)
 	return m, nil
 }
 
@@ -91,9 +127,9 @@ func (i *Inbox[M]) Add(inboxItem InboxItem) {
 }
 
 // Delete an email
-func (i *Inbox[M]) Delete(position int) error {
+func (i *Inbox[M]) Delete(ctx context.Context, position int) error {
 	mail := i.InboxItems[position]
-	if err := i.client.DeleteMail(i.Name, mail.ID); err != nil {
+	if err := i.client.DeleteMail(ctx, i.Name, mail.ID); err != nil {
 		return err
 	}
 
@@ -102,12 +138,12 @@ func (i *Inbox[M]) Delete(position int) error {
 }
 
 // Flush empties an inbox
-func (i *Inbox[M]) Flush() error {
+func (i *Inbox[M]) Flush(ctx context.Context) error {
 	if len(i.InboxItems) == 0 {
 		return nil
 	}
 
-	if err := i.client.FlushMail(i.Name, i.InboxItems[0].ID); err != nil {
+	if err := i.client.FlushMail(ctx, i.Name, i.InboxItems[0].ID); err != nil {
 		return err
 	}
 
@@ -115,61 +151,81 @@ func (i *Inbox[M]) Flush() error {
 	return nil
 }
 
+// Send sends a mail from the inbox to another yopmail address,
+// from is the address of the inbox the mail is sent from
+func (i *Inbox[M]) Send(ctx context.Context, from string, to string, subject string, body string) error {
+	return i.client.SendMail(ctx, i.Name, from, to, subject, body)
+}
+
 func (i *Inbox[M]) GetMails() []InboxItem {
 	return i.InboxItems
 }
 
 func (i *Inbox[M]) Coloured() (string, error) {
 	if i.Count() == 0 {
-		return "", errors.New("inbox is empty")
+		return "", ErrEmptyInbox
 	}
 
 	output := ""
 	for index, mail := range i.GetMails() {
-		info := struct {
-			Index          string
-			SenderName     string
-			HasSenderName  bool
-			SenderMail     string
-			HasSenderMail  bool
-			Subject        string
-			SubjectPadding string
-			SPAM           string
-		}{}
-
-		if mail.Sender != nil {
-			if mail.Sender.Name != "" {
-				info.HasSenderName = true
-				info.SenderName = color.YellowString(mail.Sender.Name)
-			} else {
-				info.SenderName = color.YellowString(noDataToDisplayMsg)
-			}
-			if mail.Sender.Mail != "" {
-				info.HasSenderMail = true
-				info.SenderMail = color.YellowString(mail.Sender.Mail)
-			} else {
-				info.SenderMail = color.YellowString(noDataToDisplayMsg)
-			}
+		s, err := ColouredItem(index+1, mail)
+		if err != nil {
+			return "", err
+		}
+		output = output + s
+	}
+	return strings.TrimRight(output, "\n"), nil
+}
+
+// ColouredItem renders a mail summary at the given position of an inbox
+func ColouredItem(index int, mail InboxItem) (string, error) {
+	info := struct {
+		Index          string
+		SenderName     string
+		HasSenderName  bool
+		SenderMail     string
+		HasSenderMail  bool
+		Subject        string
+		SubjectPadding string
+		SPAM           string
+		Date           string
+	}{}
+	if mail.Sender != nil {
+		if mail.Sender.Name != "" {
+			info.HasSenderName = true
+			info.SenderName = color.YellowString(mail.Sender.Name)
 		} else {
 			info.SenderName = color.YellowString(noDataToDisplayMsg)
-			info.SenderMail = color.YellowString(noDataToDisplayMsg)
 		}
-		if mail.Subject != "" {
-			info.Subject = color.CyanString(mail.Subject)
+		if mail.Sender.Mail != "" {
+			info.HasSenderMail = true
+			info.SenderMail = color.YellowString(mail.Sender.Mail)
 		} else {
-			info.Subject = color.CyanString(noDataToDisplayMsg)
-		}
-		if mail.IsSPAM {
-			info.SPAM = color.RedString("[SPAM]")
+			info.SenderMail = color.YellowString(noDataToDisplayMsg)
 		}
-		info.Index = strconv.Itoa(index + 1)
+	} else {
+		info.SenderName = color.YellowString(noDataToDisplayMsg)
+		info.SenderMail = color.YellowString(noDataToDisplayMsg)
+	}
+	if mail.Subject != "" {
+		info.Subject = color.CyanString(mail.Subject)
+	} else {
+		info.Subject = color.CyanString(noDataToDisplayMsg)
+	}
+	if mail.IsSPAM {
+		info.SPAM = color.RedString("[SPAM]")
+	}
+	if mail.Date != nil {
+		info.Date = color.GreenString(mail.Date.Format("2006-01-02 15:04"))
+	}
+	info.Index = strconv.Itoa(index)
 
-		for i := 0; i < len(info.Index); i++ {
-			info.SubjectPadding = info.SubjectPadding + " "
-		}
+	for i := 0; i < len(info.Index); i++ {
+		info.SubjectPadding = info.SubjectPadding + " "
+	}
 
-		var buf bytes.Buffer
-		tpl := template.Must(template.New("t").Parse(` {{.Index}} {{ if .HasSenderName -}}
+	var buf bytes.Buffer
+	tpl := template.Must(template.New("t").Parse(` {{.Index}} {{ if .HasSenderName -}}
 {{- .SenderName -}}
 {{- end -}}
 {{- if (and .HasSenderMail .HasSenderName) }} {{ end -}}
@@ -180,16 +236,15 @@ func (i *Inbox[M]) Coloured() (string, error) {
 	{{- if .HasSenderName -}}>{{- end -}}
 {{- end -}}
 {{- if .SPAM }} {{ .SPAM -}}{{- end -}}
+{{- if .Date }} {{ .Date -}}{{- end -}}
 {{- if .Subject }}
   {{.SubjectPadding}}{{ .Subject }}
 {{ end }}
 `))
-		if err := tpl.Execute(&buf, info); err != nil {
-			return "", err
-		}
-		output = output + buf.String()
+	if err := tpl.Execute(&buf, info); err != nil {
+		return "", err
 	}
-	return strings.TrimRight(output, "\n"), nil
+	return buf.String(), nil
 }
 
 func (i *Inbox[M]) JSON() (string, error) {
@@ -201,16 +256,34 @@ func (i *Inbox[M]) JSON() (string, error) {
 	return s, nil
 }
 
-// ParseInboxPages parses inbox email in given page
-func (i *Inbox[M]) ParseInboxPages(limit int) error {
-	for page := 1; page <= (limit/itemNumber)+1 && limit >= i.Count(); page++ {
-		doc, err := i.client.GetMailsPage(i.Name, page)
+// ParseInboxPages parses inbox email in given page, the mails
+// previously parsed are replaced unless the parsing fails, the
+// parsing stops once the number of mails of the inbox is reached
+// or on the first page without mail
+func (i *Inbox[M]) ParseInboxPages(ctx context.Context, limit int) error {
+	previous := i.InboxItems
+	i.InboxItems = []InboxItem{}
+	clock := i.now
+	if clock == nil {
+		clock = time.Now
+	}
+	now := clock().In(i.client.Location())
+	// the mails listed before the first day separator are today's
+	today := startOfDay(now)
+	day := &today
+	for page := 1; page <= (limit/itemNumber)+1 && i.Count() < limit; page++ {
+		doc, err := i.client.GetMailsPage(ctx, i.Name, page)
 		if err != nil {
+			i.InboxItems = previous
 			return err
 		}
 
-		parseInboxPage(doc, i)
-		time.Sleep(1 * time.Second)
+		count := i.Count()
+		day = parseInboxPage(doc, i, day, now)
+		total, ok := parseMailTotal(doc)
+		if i.Count() == count || (ok && i.Count() >= total) {
+			break
+		}
 	}
 
 	i.Shrink(limit)
@@ -218,9 +291,30 @@ func (i *Inbox[M]) ParseInboxPages(limit int) error {
 	return nil
 }
 
-// ParseInboxPage parses inbox email in given page
-func parseInboxPage[M client.MailDoc](doc *goquery.Document, inbox *Inbox[M]) {
-	doc.Find("div.m").Each(func(i int, s *goquery.Selection) {
+// parseMailTotal returns the number of mails of the inbox, it is
+// the first argument of the finrmail call made by every page
+func parseMailTotal(doc *goquery.Document) (int, bool) {
+	data := mailTotalRegexp.FindStringSubmatch(doc.Find("script").Text())
+	if len(data) < 2 {
+		return 0, false
+	}
+	total, err := strconv.Atoi(data[1])
+	return total, err == nil
+}
+
+// ParseInboxPage parses inbox email in given page, the date of a
+// mail is its time combined with the day of the last day separator,
+// the day in effect at the end of the page is returned, it is nil
+// when the last separator couldn't be parsed
+func parseInboxPage[M client.MailDoc](doc *goquery.Document, inbox *Inbox[M], day *time.Time, now time.Time) *time.Time {
+	doc.Find("div.mday, div.m").Each(func(i int, s *goquery.Selection) {
+		if s.HasClass("mday") {
+			day = nil
+			if d, ok := parseDay(s.Text(), now); ok {
+				day = &d
+			}
+			return
+		}
 		var isSPAM bool
 		name := s.Find("span.lmf").Text()
 		userEmail := name
@@ -246,8 +340,12 @@ func parseInboxPage[M client.MailDoc](doc *goquery.Document, inbox *Inbox[M]) {
 				Subject: s.Find("div.lms").Text(),
 				IsSPAM:  isSPAM,
 			}
+			if day != nil {
+				inboxItem.Date = parseTime(*day, s.Find("span.lmh").Text())
+			}
 
 			inbox.Add(inboxItem)
 		}
 	})
+	return day
 }
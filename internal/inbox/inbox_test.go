@@ -1,10 +1,15 @@
 package inbox
 
 import (
+	"context"
 	"errors"
+	"math"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/PuerkitoBio/goquery"
 	"github.com/antham/yogo/v4/internal/client"
 	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/assert"
@@ -42,12 +47,12 @@ func TestFetch(t *testing.T) {
 		},
 	}))
 
-	inbox, err := NewInbox[client.MailHTMLDoc]("test", false)
+	inbox, err := NewInbox[client.MailHTMLDoc](context.Background(), "test", client.Config{})
 	assert.NoError(t, err)
-	err = inbox.ParseInboxPages(15)
+	err = inbox.ParseInboxPages(context.Background(), 15)
 	assert.NoError(t, err)
 
-	m, err := inbox.Fetch(0)
+	m, err := inbox.Fetch(context.Background(), 0)
 	assert.NoError(t, err)
 	j, err := m.JSON()
 	assert.NoError(t, err)
@@ -86,12 +91,12 @@ func TestFetchWithDebug(t *testing.T) {
 		},
 	}))
 
-	inbox, err := NewInbox[client.MailHTMLDoc]("test", true)
+	inbox, err := NewInbox[client.MailHTMLDoc](context.Background(), "test", client.Config{EnableDebugMode: true})
 	assert.NoError(t, err)
-	err = inbox.ParseInboxPages(15)
+	err = inbox.ParseInboxPages(context.Background(), 15)
 	assert.NoError(t, err)
 
-	m, err := inbox.Fetch(0)
+	m, err := inbox.Fetch(context.Background(), 0)
 	assert.NoError(t, err)
 	j, err := m.JSON()
 	assert.NoError(t, err)
@@ -130,9 +135,9 @@ func TestCount(t *testing.T) {
 		},
 	}))
 
-	inbox, err := NewInbox[client.MailHTMLDoc]("test", false)
+	inbox, err := NewInbox[client.MailHTMLDoc](context.Background(), "test", client.Config{})
 	assert.NoError(t, err)
-	err = inbox.ParseInboxPages(15)
+	err = inbox.ParseInboxPages(context.Background(), 15)
 	assert.NoError(t, err)
 	assert.Equal(t, inbox.Count(), 15)
 }
@@ -184,34 +189,107 @@ func TestParseInboxPages(t *testing.T) {
 		},
 	}))
 
-	inbox, err := NewInbox[client.MailHTMLDoc]("test", false)
+	inbox, err := NewInbox[client.MailHTMLDoc](context.Background(), "test", client.Config{})
 	assert.NoError(t, err)
 
-	err = inbox.ParseInboxPages(29)
+	err = inbox.ParseInboxPages(context.Background(), 29)
 
 	assert.NoError(t, err)
 	assert.Equal(t, "test", inbox.Name)
 	assert.Equal(t, 29, inbox.Count())
-	m, err := inbox.Fetch(0)
+	m, err := inbox.Fetch(context.Background(), 0)
 	assert.NoError(t, err)
 	j, err := m.JSON()
 	assert.NoError(t, err)
 	assert.Contains(t, j, "e_ZwRjAwRmZGtmAwZ1ZQNjAwt5AQZmZj==")
-	m, err = inbox.Fetch(28)
+	m, err = inbox.Fetch(context.Background(), 28)
 	assert.NoError(t, err)
 	j, err = m.JSON()
 	assert.NoError(t, err)
 	assert.Contains(t, j, "e_ZwRjAwRmZGtmZQR0ZQNjAwt2BGV5BN==")
-	m, err = inbox.Fetch(13)
+	m, err = inbox.Fetch(context.Background(), 13)
 	assert.NoError(t, err)
 	j, err = m.JSON()
 	assert.NoError(t, err)
 	assert.Contains(t, j, "e_ZwRjAwRmZGtmZwR0ZQNjAwt3AmxlZN==")
-	m, err = inbox.Fetch(14)
+	m, err = inbox.Fetch(context.Background(), 14)
 	assert.NoError(t, err)
 	j, err = m.JSON()
 	assert.NoError(t, err)
 	assert.Contains(t, j, "e_ZwRjAwRmZGtmZwN3ZQNjAwt3AmZlAD==")
+
+	assert.NoError(t, inbox.ParseInboxPages(context.Background(), 3))
+	assert.Equal(t, 3, inbox.Count())
+	assert.Equal(t, "e_ZwRjAwRmZGtmAwZ1ZQNjAwt5AQZmZj==", inbox.GetMails()[0].ID)
+
+	page2URL := "GET https://yopmail.com/en/inbox?ad=0&ctrl=&d=&id=&login=test&p=2&r_c=&scrl=&spam=true&v=4.8&yj=VZGV5AmpjZwp5ZGNmZwL0BQH&yp=UAQDkAGH2Amp2Zmt0ZmVmAGp"
+	calls := httpmock.GetCallCountInfo()[page2URL]
+	assert.NoError(t, inbox.ParseInboxPages(context.Background(), 15))
+	assert.Equal(t, 15, inbox.Count())
+	assert.Equal(t, calls, httpmock.GetCallCountInfo()[page2URL])
+}
+
+func TestParseInboxPagesStopsAtTotal(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	assert.NoError(t, registerResponders([]responder{
+		{
+			"GET",
+			"https://yopmail.com",
+			"features/main_page.html",
+		},
+		{
+			"GET",
+			"https://yopmail.com/ver/4.8/webmail.js",
+			"features/webmail.js",
+		},
+	}))
+	pageURL := "https://yopmail.com/en/inbox?ad=0&ctrl=&d=&id=&login=test&p=1&r_c=&scrl=&spam=true&v=4.8&yj=VZGV5AmpjZwp5ZGNmZwL0BQH&yp=UAQDkAGH2Amp2Zmt0ZmVmAGp"
+	httpmock.RegisterResponder("GET", pageURL, httpmock.NewStringResponder(200, `<div class="m" id="e_1"><button class="lm"><span class="lmf">Shop</span><div class="lms">Hello</div></button></div>
+<div class="m" id="e_2"><button class="lm"><span class="lmf">Shop</span><div class="lms">Bye</div></button></div>
+<script>function fin() { w.finrmail(2, 1, 0, 0, 0, 'alt.xm-doh3nzhv', ''); }</script>`))
+
+	inbox, err := NewInbox[client.MailHTMLDoc](context.Background(), "test", client.Config{})
+	assert.NoError(t, err)
+
+	assert.NoError(t, inbox.ParseInboxPages(context.Background(), math.MaxInt32))
+	assert.Equal(t, 2, inbox.Count())
+	assert.Equal(t, 1, httpmock.GetCallCountInfo()["GET "+pageURL])
+}
+
+func TestParseInboxPagesWithCancelledContext(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	assert.NoError(t, registerResponders([]responder{
+		{
+			"GET",
+			"https://yopmail.com/en/inbox?ad=0&ctrl=&d=&id=&login=test&p=1&r_c=&scrl=&spam=true&v=4.8&yj=VZGV5AmpjZwp5ZGNmZwL0BQH&yp=UAQDkAGH2Amp2Zmt0ZmVmAGp",
+			"features/inbox_page_1.html",
+		},
+		{
+			"GET",
+			"https://yopmail.com",
+			"features/main_page.html",
+		},
+		{
+			"GET",
+			"https://yopmail.com/ver/4.8/webmail.js",
+			"features/webmail.js",
+		},
+	}))
+
+	inbox, err := NewInbox[client.MailHTMLDoc](context.Background(), "test", client.Config{RateLimit: client.RateLimit{RequestsPerMinute: 1, Burst: 3}})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err = inbox.ParseInboxPages(ctx, 29)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
 }
 
 func TestShrink(t *testing.T) {
@@ -256,18 +334,18 @@ func TestShrink(t *testing.T) {
 		},
 	}))
 
-	inbox, err := NewInbox[client.MailHTMLDoc]("test", false)
+	inbox, err := NewInbox[client.MailHTMLDoc](context.Background(), "test", client.Config{})
 	assert.NoError(t, err)
 
-	err = inbox.ParseInboxPages(19)
+	err = inbox.ParseInboxPages(context.Background(), 19)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 19, inbox.Count())
-	m, err := inbox.Fetch(0)
+	m, err := inbox.Fetch(context.Background(), 0)
 	assert.NoError(t, err)
 	_, err = m.JSON()
 	assert.NoError(t, err)
-	_, err = inbox.Fetch(18)
+	_, err = inbox.Fetch(context.Background(), 18)
 	assert.NoError(t, err)
 }
 
@@ -298,10 +376,10 @@ func TestShrinkEmptyInbox(t *testing.T) {
 		},
 	}))
 
-	inbox, err := NewInbox[client.MailHTMLDoc]("test", false)
+	inbox, err := NewInbox[client.MailHTMLDoc](context.Background(), "test", client.Config{})
 	assert.NoError(t, err)
 
-	err = inbox.ParseInboxPages(1)
+	err = inbox.ParseInboxPages(context.Background(), 1)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 0, inbox.Count())
@@ -339,10 +417,10 @@ func TestShrinkWithLimitGreaterThanNumberOfMessagesAvailable(t *testing.T) {
 		},
 	}))
 
-	inbox, err := NewInbox[client.MailHTMLDoc]("test", false)
+	inbox, err := NewInbox[client.MailHTMLDoc](context.Background(), "test", client.Config{})
 	assert.NoError(t, err)
 
-	err = inbox.ParseInboxPages(18)
+	err = inbox.ParseInboxPages(context.Background(), 18)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 15, inbox.Count())
@@ -375,10 +453,10 @@ func TestGetAll(t *testing.T) {
 		},
 	}))
 
-	inbox, err := NewInbox[client.MailHTMLDoc]("test", false)
+	inbox, err := NewInbox[client.MailHTMLDoc](context.Background(), "test", client.Config{})
 	assert.NoError(t, err)
 
-	err = inbox.ParseInboxPages(29)
+	err = inbox.ParseInboxPages(context.Background(), 29)
 	mails := inbox.InboxItems
 
 	assert.NoError(t, err)
@@ -429,12 +507,12 @@ func TestFlush(t *testing.T) {
 		},
 	}))
 
-	inbox, err := NewInbox[client.MailHTMLDoc]("test", false)
+	inbox, err := NewInbox[client.MailHTMLDoc](context.Background(), "test", client.Config{})
 	assert.NoError(t, err)
 
-	err = inbox.ParseInboxPages(15)
+	err = inbox.ParseInboxPages(context.Background(), 15)
 	assert.NoError(t, err)
-	err = inbox.Flush()
+	err = inbox.Flush(context.Background())
 	assert.NoError(t, err)
 }
 
@@ -460,12 +538,12 @@ func TestFlushEmptyInbox(t *testing.T) {
 		},
 	}))
 
-	inbox, err := NewInbox[client.MailHTMLDoc]("test", false)
+	inbox, err := NewInbox[client.MailHTMLDoc](context.Background(), "test", client.Config{})
 	assert.NoError(t, err)
 
-	err = inbox.ParseInboxPages(1)
+	err = inbox.ParseInboxPages(context.Background(), 1)
 	assert.NoError(t, err)
-	err = inbox.Flush()
+	err = inbox.Flush(context.Background())
 	assert.NoError(t, err)
 
 	assert.Equal(t, 0, inbox.Count())
@@ -498,16 +576,44 @@ func TestDelete(t *testing.T) {
 		},
 	}))
 
-	inbox, err := NewInbox[client.MailHTMLDoc]("test", false)
+	inbox, err := NewInbox[client.MailHTMLDoc](context.Background(), "test", client.Config{})
 	assert.NoError(t, err)
 
-	err = inbox.ParseInboxPages(1)
-	assert.NoError(t, inbox.Delete(0))
+	err = inbox.ParseInboxPages(context.Background(), 1)
+	assert.NoError(t, inbox.Delete(context.Background(), 0))
 
 	assert.Equal(t, 1, httpmock.GetCallCountInfo()["GET https://yopmail.com/en/inbox?ad=0&ctrl=&d=e_ZwRjAwRmZGtmAwZ1ZQNjAwt5AQZmZj%3D%3D&id=&login=test&p=1&r_c=&v=4.8&yj=VZGV5AmpjZwp5ZGNmZwL0BQH&yp=UAQDkAGH2Amp2Zmt0ZmVmAGp"])
 	assert.NoError(t, err)
 }
 
+func TestSend(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	assert.NoError(t, registerResponders([]responder{
+		{
+			"GET",
+			"https://yopmail.com",
+			"features/main_page.html",
+		},
+		{
+			"GET",
+			"https://yopmail.com/ver/4.8/webmail.js",
+			"features/webmail.js",
+		},
+		{
+			"POST",
+			"https://yopmail.com/en/writepost?v=4.8&yj=VZGV5AmpjZwp5ZGNmZwL0BQH&yp=UAQDkAGH2Amp2Zmt0ZmVmAGp",
+			"features/sent.html",
+		},
+	}))
+
+	inbox, err := NewInbox[client.MailHTMLDoc](context.Background(), "test", client.Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, inbox.Send(context.Background(), "test@yopmail.fr", "test2@yopmail.com", "subject", "body"))
+	assert.Equal(t, 1, httpmock.GetCallCountInfo()["POST https://yopmail.com/en/writepost?v=4.8&yj=VZGV5AmpjZwp5ZGNmZwL0BQH&yp=UAQDkAGH2Amp2Zmt0ZmVmAGp"])
+}
+
 func TestColoured(t *testing.T) {
 	type scenario struct {
 		name               string
@@ -517,6 +623,8 @@ func TestColoured(t *testing.T) {
 		errorExpected      error
 	}
 
+	date := time.Date(2023, 8, 13, 22, 45, 0, 0, time.UTC)
+
 	scenarios := []scenario{
 		{
 			name: "No mails in the inbox",
@@ -548,6 +656,7 @@ func TestColoured(t *testing.T) {
 							Name: "test2",
 						},
 						Subject: "test2 subject",
+						Date:    &date,
 					},
 					{
 						ID:     "0243583b-7b58-40cb-a2b7-c09d79673334",
@@ -605,7 +714,7 @@ func TestColoured(t *testing.T) {
 			outputExpected: ` 1 test1 <test1@protonmail.com> [SPAM]
    test1 subject
 
- 2 test2 <test2@protonmail.com>
+ 2 test2 <test2@protonmail.com> 2023-08-13 22:45
    test2 subject
 
  3 test3 <test3@protonmail.com> [SPAM]
@@ -634,7 +743,7 @@ func TestColoured(t *testing.T) {
 
  11 [no data to display]
     test11 subject`,
-			jsonOutputExpected: `{"name":"test","mails":[{"id":"02d3583b-7b58-40cb-a2b7-c09d79673334","sender":{"mail":"test1@protonmail.com","name":"test1"},"subject":"test1 subject","isSPAM":true},{"id":"0343583b-7b58-40cb-a2b7-c09d79673334","sender":{"mail":"test2@protonmail.com","name":"test2"},"subject":"test2 subject","isSPAM":false},{"id":"0243583b-7b58-40cb-a2b7-c09d79673334","sender":{"mail":"test3@protonmail.com","name":"test3"},"subject":"test3 subject","isSPAM":true},{"id":"0783583b-7b58-40cb-a2b7-c09d79673334","sender":{"name":"test4"},"subject":"test4 subject","isSPAM":false},{"id":"0903583b-7b58-40cb-a2b7-c09d79673334","sender":{"mail":"test5@protonmail.com"},"subject":"test5 subject","isSPAM":false},{"id":"12d3583b-7b58-40cb-a2b7-c09d79673334","sender":{"mail":"test6@protonmail.com","name":"test6"},"subject":"","isSPAM":false},{"id":"67d3583b-7b58-40cb-a2b7-c09d79673334","sender":{},"subject":"test7 subject","isSPAM":false},{"id":"89d3583b-7b58-40cb-a2b7-c09d79673334","subject":"test8 subject","isSPAM":false},{"id":"f44cf3b8-f6a4-4b75-b734-cb1553b23cf6","subject":"test9 subject","isSPAM":false},{"id":"f207be30-fad5-4d73-aa30-f69cb2a5ebac","subject":"test10 subject","isSPAM":false},{"id":"d64c2eeb-9ff6-4d33-b4dc-034557805308","subject":"test11 subject","isSPAM":false}]}`,
+			jsonOutputExpected: `{"name":"test","mails":[{"id":"02d3583b-7b58-40cb-a2b7-c09d79673334","sender":{"mail":"test1@protonmail.com","name":"test1"},"subject":"test1 subject","isSPAM":true},{"id":"0343583b-7b58-40cb-a2b7-c09d79673334","sender":{"mail":"test2@protonmail.com","name":"test2"},"subject":"test2 subject","date":"2023-08-13T22:45:00Z","isSPAM":false},{"id":"0243583b-7b58-40cb-a2b7-c09d79673334","sender":{"mail":"test3@protonmail.com","name":"test3"},"subject":"test3 subject","isSPAM":true},{"id":"0783583b-7b58-40cb-a2b7-c09d79673334","sender":{"name":"test4"},"subject":"test4 subject","isSPAM":false},{"id":"0903583b-7b58-40cb-a2b7-c09d79673334","sender":{"mail":"test5@protonmail.com"},"subject":"test5 subject","isSPAM":false},{"id":"12d3583b-7b58-40cb-a2b7-c09d79673334","sender":{"mail":"test6@protonmail.com","name":"test6"},"subject":"","isSPAM":false},{"id":"67d3583b-7b58-40cb-a2b7-c09d79673334","sender":{},"subject":"test7 subject","isSPAM":false},{"id":"89d3583b-7b58-40cb-a2b7-c09d79673334","subject":"test8 subject","isSPAM":false},{"id":"f44cf3b8-f6a4-4b75-b734-cb1553b23cf6","subject":"test9 subject","isSPAM":false},{"id":"f207be30-fad5-4d73-aa30-f69cb2a5ebac","subject":"test10 subject","isSPAM":false},{"id":"d64c2eeb-9ff6-4d33-b4dc-034557805308","subject":"test11 subject","isSPAM":false}]}`,
 		},
 	}
 
@@ -675,3 +784,133 @@ func registerResponders(responders []responder) error {
 	}
 	return nil
 }
+
+func TestParseInboxPagesStopsOnEmptyPage(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	assert.NoError(t, registerResponders([]responder{
+		{
+			"GET",
+			"https://yopmail.com/en/inbox?ad=0&ctrl=&d=&id=&login=test&p=1&r_c=&scrl=&spam=true&v=4.8&yj=VZGV5AmpjZwp5ZGNmZwL0BQH&yp=UAQDkAGH2Amp2Zmt0ZmVmAGp",
+			"features/inbox_page_1.html",
+		},
+		{
+			"GET",
+			"https://yopmail.com/en/inbox?ad=0&ctrl=&d=&id=&login=test&p=2&r_c=&scrl=&spam=true&v=4.8&yj=VZGV5AmpjZwp5ZGNmZwL0BQH&yp=UAQDkAGH2Amp2Zmt0ZmVmAGp",
+			"features/inbox_empty.html",
+		},
+		{
+			"GET",
+			"https://yopmail.com",
+			"features/main_page.html",
+		},
+		{
+			"GET",
+			"https://yopmail.com/ver/4.8/webmail.js",
+			"features/webmail.js",
+		},
+	}))
+
+	inbox, err := NewInbox[client.MailHTMLDoc](context.Background(), "test", client.Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, inbox.ParseInboxPages(context.Background(), 100))
+	assert.Equal(t, 15, inbox.Count())
+}
+
+func TestParseInboxPageDates(t *testing.T) {
+	type scenario struct {
+		name     string
+		page     string
+		day      *time.Time
+		expected []*time.Time
+		lastDay  *time.Time
+	}
+
+	now := time.Date(2023, 8, 16, 10, 30, 0, 0, time.UTC)
+	date := func(day int, hour int, min int) *time.Time {
+		d := time.Date(2023, 8, day, hour, min, 0, 0, time.UTC)
+		return &d
+	}
+	mail := func(ID string, hour string) string {
+		return `<div class="m" id="` + ID + `"><span class="lmh">` + hour + `</span><span class="lmf">John</span><div class="lms">Hello</div></div>`
+	}
+
+	scenarios := []scenario{
+		{
+			name:     "Combine the day separators and the times",
+			page:     `<div class="mday">aujourd'hui</div>` + mail("a", "09:12") + `<div class="mday">hier</div>` + mail("b", "23:14") + `<div class="mday">13/08/2023</div>` + mail("c", "08:00"),
+			day:      date(16, 0, 0),
+			expected: []*time.Time{date(16, 9, 12), date(15, 23, 14), date(13, 8, 0)},
+			lastDay:  date(13, 0, 0),
+		},
+		{
+			name:     "Continue the day of the previous page",
+			page:     mail("a", "22:01") + `<div class="mday">lundi</div>` + mail("b", "21:00"),
+			day:      date(15, 0, 0),
+			expected: []*time.Time{date(15, 22, 1), date(14, 21, 0)},
+			lastDay:  date(14, 0, 0),
+		},
+		{
+			name:     "Unknown day separator",
+			page:     `<div class="mday">un jour</div>` + mail("a", "22:01") + mail("b", "whenever"),
+			day:      date(16, 0, 0),
+			expected: []*time.Time{nil, nil},
+		},
+		{
+			name:     "Unknown time",
+			page:     mail("a", ""),
+			day:      date(16, 0, 0),
+			expected: []*time.Time{nil},
+			lastDay:  date(16, 0, 0),
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<div class="mctn">` + s.page + `</div>`))
+			assert.NoError(t, err)
+			in := NewInboxWithClient("test", client.Client[client.MailHTMLDoc]{})
+			lastDay := parseInboxPage(doc, in, s.day, now)
+			assert.Equal(t, s.lastDay, lastDay)
+			dates := []*time.Time{}
+			for _, m := range in.GetMails() {
+				dates = append(dates, m.Date)
+			}
+			assert.Equal(t, s.expected, dates)
+		})
+	}
+}
+
+func TestParseInboxPagesDates(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	assert.NoError(t, registerResponders([]responder{
+		{
+			"GET",
+			"https://yopmail.com/en/inbox?ad=0&ctrl=&d=&id=&login=test&p=1&r_c=&scrl=&spam=true&v=4.8&yj=VZGV5AmpjZwp5ZGNmZwL0BQH&yp=UAQDkAGH2Amp2Zmt0ZmVmAGp",
+			"features/inbox_page_1.html",
+		},
+		{
+			"GET",
+			"https://yopmail.com",
+			"features/main_page.html",
+		},
+		{
+			"GET",
+			"https://yopmail.com/ver/4.8/webmail.js",
+			"features/webmail.js",
+		},
+	}))
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	assert.NoError(t, err)
+	in, err := NewInbox[client.MailHTMLDoc](context.Background(), "test", client.Config{Location: tokyo})
+	assert.NoError(t, err)
+	in.now = func() time.Time {
+		return time.Date(2023, 8, 13, 21, 0, 0, 0, time.UTC)
+	}
+	assert.NoError(t, in.ParseInboxPages(context.Background(), 1))
+	assert.Equal(t, time.Date(2023, 8, 14, 20, 36, 0, 0, tokyo), *in.GetMails()[0].Date)
+}
@@ -0,0 +1,102 @@
+package mail
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var urlRegexp = regexp.MustCompile(`https?://[^\s<>"'()\[\]]+`)
+
+// Text returns the text rendering of the mail
+func (m *HTMLMail) Text() string {
+	return m.Body
+}
+
+// URLs returns the links of the mail followed by
+// the URLs written in its text, without duplicates
+func (m *HTMLMail) URLs() []string {
+	return Dedup(append(slices.Clone(m.Links), FindURLs(m.Body)...))
+}
+
+// Text returns the decoded text parts of the mail,
+// the HTML parts are converted to text
+func (m *SourceMail) Text() string {
+	plain, html := m.textParts()
+	texts := plain
+	for _, h := range html {
+		texts = append(texts, parseHTML(h, nil))
+	}
+	return strings.Join(texts, "\n")
+}
+
+// URLs returns the links of the HTML parts of the mail followed
+// by the URLs written in its text parts, without duplicates
+func (m *SourceMail) URLs() []string {
+	plain, html := m.textParts()
+	urls := []string{}
+	for _, h := range html {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(h))
+		if err != nil {
+			continue
+		}
+		urls = append(urls, parseLinks(doc.Selection)...)
+		urls = append(urls, FindURLs(doc.Text())...)
+	}
+	for _, p := range plain {
+		urls = append(urls, FindURLs(p)...)
+	}
+	return Dedup(urls)
+}
+
+// textParts returns the decoded text/plain and text/html parts of the mail
+func (m *SourceMail) textParts() ([]string, []string) {
+	plain, html := []string{}, []string{}
+	for _, p := range m.mimeTree().leaves() {
+		switch {
+		case !p.isBody():
+		case p.ContentType == "text/html":
+			html = append(html, p.Text)
+		default:
+			plain = append(plain, p.Text)
+		}
+	}
+	return plain, html
+}
+
+// parseLinks returns the absolute links of the anchors of a document
+func parseLinks(s *goquery.Selection) []string {
+	links := []string{}
+	s.Find("a[href]").Each(func(i int, a *goquery.Selection) {
+		href := strings.TrimSpace(a.AttrOr("href", ""))
+		if strings.HasPrefix(href, "http://") || strings.HasPrefix(href, "https://") {
+			links = append(links, href)
+		}
+	})
+	return Dedup(links)
+}
+
+// FindURLs returns the URLs written in a text,
+// the trailing punctuation is not part of them
+func FindURLs(text string) []string {
+	urls := []string{}
+	for _, u := range urlRegexp.FindAllString(text, -1) {
+		urls = append(urls, strings.TrimRight(u, ".,;:!?"))
+	}
+	return urls
+}
+
+// Dedup removes the duplicated values, the first occurrence is kept
+func Dedup(values []string) []string {
+	seen := map[string]bool{}
+	result := []string{}
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
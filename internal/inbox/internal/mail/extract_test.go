@@ -0,0 +1,82 @@
+package mail
+
+import (
+	"testing"
+
+	"github.com/antham/yogo/v4/internal/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTMLMailExtract(t *testing.T) {
+	m, err := Parse[client.MailHTMLDoc](getDoc[client.MailHTMLDoc](t, "html_mail.html"))
+	assert.NoError(t, err)
+
+	mail := m.(*HTMLMail)
+	assert.Equal(t, []string{
+		"https://fectment.page.link/Ymry",
+		"https://fectment.page.link/CF1b",
+		"https://matering.page.link/bAmq",
+		"https://exteleer.page.link/kjcS",
+	}, mail.Links)
+	assert.Equal(t, mail.Links, mail.URLs())
+	assert.Equal(t, mail.Body, mail.Text())
+}
+
+func TestSourceMailExtract(t *testing.T) {
+	type scenario struct {
+		name string
+		mail *SourceMail
+		text string
+		urls []string
+	}
+
+	scenarios := []scenario{
+		{
+			name: "Quoted-printable HTML mail",
+			mail: &SourceMail{
+				Headers: map[string][]string{"Content-Type": {"text/html; charset=utf-8"}, "Content-Transfer-Encoding": {"quoted-printable"}},
+				Body:    "<p>Visit <a href=3D\"https://example.com/a?b=3Dc\">us</a> or https://example.com/d=\n.</p>",
+			},
+			text: "Visit us ( https://example.com/a?b=c ) or https://example.com/d.",
+			urls: []string{"https://example.com/a?b=c", "https://example.com/d"},
+		},
+		{
+			name: "Mail without content type",
+			mail: &SourceMail{
+				Body: "Code: 1234, see https://example.com/code.",
+			},
+			text: "Code: 1234, see https://example.com/code.",
+			urls: []string{"https://example.com/code"},
+		},
+		{
+			name: "Multipart mail with an attachment",
+			mail: &SourceMail{
+				Headers: map[string][]string{"Content-Type": {`multipart/mixed; boundary="outer"`}},
+				Body: "--outer\r\n" +
+					"Content-Type: multipart/alternative; boundary=inner\r\n\r\n" +
+					"--inner\r\n" +
+					"Content-Type: text/plain\r\n" +
+					"Content-Transfer-Encoding: base64\r\n\r\n" +
+					"WW91ciBjb2RlIGlzIDQyNDI0Mi4gaHR0cHM6Ly9l\r\neGFtcGxlLmNvbS9wbGFpbg==\r\n" +
+					"--inner\r\n" +
+					"Content-Type: text/html\r\n\r\n" +
+					"<a href=\"https://example.com/html\">link</a>\r\n" +
+					"--inner--\r\n" +
+					"--outer\r\n" +
+					"Content-Type: text/plain\r\n" +
+					"Content-Disposition: attachment; filename=\"a.txt\"\r\n\r\n" +
+					"https://example.com/attachment\r\n" +
+					"--outer--\r\n",
+			},
+			text: "Your code is 424242. https://example.com/plain\nlink ( https://example.com/html )",
+			urls: []string{"https://example.com/html", "https://example.com/plain"},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			assert.Equal(t, scenario.text, scenario.mail.Text())
+			assert.Equal(t, scenario.urls, scenario.mail.URLs())
+		})
+	}
+}
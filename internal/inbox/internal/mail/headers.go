@@ -0,0 +1,79 @@
+package mail
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	gomail "net/mail"
+	"strings"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// addressHeaders are the headers parsed into address lists
+var addressHeaders = []string{"From", "To", "Cc", "Reply-To"}
+
+// wordDecoder decodes RFC 2047 encoded-words,
+// the legacy charsets are supported
+var wordDecoder = &mime.WordDecoder{CharsetReader: charsetReader}
+
+var addressParser = &gomail.AddressParser{WordDecoder: wordDecoder}
+
+// Address is a mailbox of an address header
+type Address struct {
+	Name string `json:"name,omitempty"`
+	Mail string `json:"mail"`
+}
+
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	e, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported charset %s : %w", charset, err)
+	}
+	return e.NewDecoder().Reader(input), nil
+}
+
+// decodeHeader decodes the encoded-words of a header value,
+// the value is kept as is when it is malformed
+func decodeHeader(value string) string {
+	decoded, err := wordDecoder.DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+	return decoded
+}
+
+// decodeHeaders returns a copy of the headers with their values decoded
+func decodeHeaders(headers map[string][]string) map[string][]string {
+	decoded := make(map[string][]string, len(headers))
+	for k, vs := range headers {
+		for _, v := range vs {
+			decoded[k] = append(decoded[k], decodeHeader(v))
+		}
+	}
+	return decoded
+}
+
+// parseAddresses returns the address lists of the address headers,
+// the headers that can't be parsed are left out
+func parseAddresses(headers map[string][]string) map[string][]Address {
+	addresses := map[string][]Address{}
+	for _, k := range addressHeaders {
+		for _, v := range headers[k] {
+			list, err := addressParser.ParseList(v)
+			if err != nil {
+				continue
+			}
+			for _, a := range list {
+				addresses[k] = append(addresses[k], Address{Name: a.Name, Mail: a.Address})
+			}
+		}
+	}
+	return addresses
+}
+
+// parseAddress parses a single mailbox written as free text,
+// the line breaks of the text are folded
+func parseAddress(s string) (*gomail.Address, error) {
+	return addressParser.Parse(strings.Join(strings.Fields(s), " "))
+}
@@ -0,0 +1,47 @@
+package mail
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeHeader(t *testing.T) {
+	type scenario struct {
+		name     string
+		value    string
+		expected string
+	}
+
+	scenarios := []scenario{
+		{"plain value", "Hello world", "Hello world"},
+		{"UTF-8 base64", "=?UTF-8?B?QmllbnZlbnVlIMOgIGJvcmQ=?=", "Bienvenue à bord"},
+		{"ISO-8859-1 quoted-printable", "=?ISO-8859-1?Q?Caf=E9?= au lait", "Café au lait"},
+		{"windows-1252", "=?windows-1252?Q?=80_100?=", "€ 100"},
+		{"KOI8-R", "=?KOI8-R?B?8NLJ18XU?=", "Привет"},
+		{"adjacent encoded-words", "=?UTF-8?Q?a?= =?UTF-8?Q?b?=", "ab"},
+		{"unknown charset", "=?x-unknown?Q?abc?=", "=?x-unknown?Q?abc?="},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			assert.Equal(t, s.expected, decodeHeader(s.value))
+		})
+	}
+}
+
+func TestParseAddresses(t *testing.T) {
+	addresses := parseAddresses(map[string][]string{
+		"From":     {`"Doe, John" <john@example.com>`},
+		"To":       {`=?ISO-8859-1?Q?Andr=E9?= <andre@example.com>, test@yopmail.com`},
+		"Cc":       {`not an address`},
+		"Reply-To": {`=?UTF-8?B?5pel5pys?= <jp@example.com>`},
+		"Subject":  {`a@example.com`},
+	})
+
+	assert.Equal(t, map[string][]Address{
+		"From":     {{Name: "Doe, John", Mail: "john@example.com"}},
+		"To":       {{Name: "André", Mail: "andre@example.com"}, {Mail: "test@yopmail.com"}},
+		"Reply-To": {{Name: "日本", Mail: "jp@example.com"}},
+	}, addresses)
+}
@@ -26,6 +26,7 @@ type HTMLMail struct {
 	Subject string     `json:"subject,omitempty"`
 	Date    *time.Time `json:"date,omitempty"`
 	Body    string     `json:"body,omitempty"`
+	Links   []string   `json:"links,omitempty"`
 	IsSPAM  bool       `json:"isSPAM"`
 }
 
@@ -34,6 +35,11 @@ func (m *HTMLMail) SetID(ID string) {
 }
 
 func (m *HTMLMail) Coloured() (string, error) {
+	return colouredMail(m.Sender, m.Subject, m.Date, m.Body)
+}
+
+// colouredMail renders the headers and the body of a mail
+func colouredMail(sender *Sender, subject string, date *time.Time, body string) (string, error) {
 	info := struct {
 		HasSenderName bool
 		SenderName    string
@@ -45,16 +51,16 @@ func (m *HTMLMail) Coloured() (string, error) {
 		Body          string
 	}{}
 
-	if m.Sender != nil {
-		if m.Sender.Name != "" {
-			info.SenderName = color.MagentaString(m.Sender.Name)
+	if sender != nil {
+		if sender.Name != "" {
+			info.SenderName = color.MagentaString(sender.Name)
 			info.HasSenderName = true
 		} else {
 			info.SenderName = color.MagentaString(noDataToDisplayMsg)
 		}
-		if m.Sender.Mail != "" {
+		if sender.Mail != "" {
 			info.HasSenderMail = true
-			info.SenderMail = color.MagentaString(m.Sender.Mail)
+			info.SenderMail = color.MagentaString(sender.Mail)
 		} else {
 			info.SenderMail = color.MagentaString(noDataToDisplayMsg)
 		}
@@ -62,18 +68,18 @@ func (m *HTMLMail) Coloured() (string, error) {
 		info.SenderName = color.MagentaString(noDataToDisplayMsg)
 		info.SenderMail = color.MagentaString(noDataToDisplayMsg)
 	}
-	if m.Subject != "" {
-		info.Subject = color.YellowString(m.Subject)
+	if subject != "" {
+		info.Subject = color.YellowString(subject)
 	} else {
 		info.Subject = color.YellowString(noDataToDisplayMsg)
 	}
-	if m.Date != nil {
-		info.Date = color.GreenString(m.Date.Format("2006-01-02 15:04"))
+	if date != nil {
+		info.Date = color.GreenString(date.Format("2006-01-02 15:04"))
 	} else {
 		info.Date = color.GreenString(noDataToDisplayMsg)
 	}
-	if m.Body != "" {
-		info.Body = color.CyanString(m.Body)
+	if body != "" {
+		info.Body = color.CyanString(body)
 	} else {
 		info.Body = color.CyanString(noDataToDisplayMsg)
 	}
@@ -109,10 +115,13 @@ func (m *HTMLMail) JSON() (string, error) {
 }
 
 func parseFrom(s string) (string, string) {
+	if a, err := parseAddress(s); err == nil {
+		return a.Name, a.Address
+	}
 	re := regexp.MustCompile(`(?s)(.+?) <(.+?)>`)
 	matches := re.FindStringSubmatch(s)
 	if len(matches) == 3 {
-		return strings.TrimSpace(matches[1]), matches[2]
+		return decodeHeader(strings.TrimSpace(matches[1])), matches[2]
 	}
 	re = regexp.MustCompile(`<(.+?)>`)
 	matches = re.FindStringSubmatch(s)
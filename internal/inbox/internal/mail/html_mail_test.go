@@ -157,6 +157,18 @@ func TestParseFrom(t *testing.T) {
 			resultName:  "",
 			resultEmail: "john.doe@unknown.com",
 		},
+		{
+			name:        "parse a quoted name containing a comma",
+			fromArg:     `"Doe, John" <john.doe@unknown.com>`,
+			resultName:  "Doe, John",
+			resultEmail: "john.doe@unknown.com",
+		},
+		{
+			name:        "parse an encoded name",
+			fromArg:     "=?ISO-8859-1?Q?Andr=E9?= <andre@unknown.com>",
+			resultName:  "André",
+			resultEmail: "andre@unknown.com",
+		},
 		{
 			name:        "no email nor name to parse",
 			fromArg:     "",
@@ -1,11 +1,16 @@
 package mail
 
 import (
+	"bytes"
+	"errors"
+
 	"github.com/PuerkitoBio/goquery"
 	"github.com/antham/yogo/v4/internal/client"
 	"io"
 	gomail "net/mail"
+	"net/textproto"
 	"strings"
+	"time"
 )
 
 const noDataToDisplayMsg = "[no data to display]"
@@ -28,37 +33,63 @@ func Parse[M client.MailDoc](doc M) (RenderIdentifier, error) {
 	var m RenderIdentifier
 	switch any(doc).(type) {
 	case client.MailHTMLDoc:
+		if doc.Find("div#mail").Length() == 0 {
+			return m, &client.ParseError{Page: "mail", Err: errors.New("mail content not found")}
+		}
 		mail := &HTMLMail{}
-		doc.Find("body div.fl .ellipsis").Each(func(i int, s *goquery.Selection) {
-			switch i {
-			case 0:
-				mail.Subject = strings.TrimSpace(s.Text())
-			case 1:
-				mail.Sender = &Sender{}
-				mail.Sender.Name, mail.Sender.Mail = parseFrom(s.Text())
-			case 2:
-				mail.Date = parseDate(strings.Join(strings.Fields(s.Text()), " "))
-			}
-		})
+		mail.Sender, mail.Subject, mail.Date = parseHeaders(doc.Find("body div.fl .ellipsis"))
 		mail.Body = parseHTML(doc.Find("div#mail").Html())
+		mail.Links = parseLinks(doc.Find("div#mail"))
+		m = mail
+	case client.MailTextDoc:
+		if doc.Find("div#mail").Length() == 0 {
+			return m, &client.ParseError{Page: "mail text", Err: errors.New("mail content not found")}
+		}
+		mail := &TextMail{}
+		mail.Sender, mail.Subject, mail.Date = parseHeaders(doc.Find("body div.fl .ellipsis"))
+		content := doc.Find("div#mail")
+		content.Find("br").ReplaceWithHtml("\n")
+		mail.Body = strings.TrimSpace(content.Text())
 		m = mail
 	case client.MailSourceDoc:
-		msg, err := gomail.ReadMessage(
-			strings.NewReader(
-				doc.Find("body div#mail pre").Text(),
-			),
-		)
+		message := doc.Find("body div#mail pre").Text()
+		msg, err := gomail.ReadMessage(strings.NewReader(message))
 		if err != nil {
-			return m, err
+			return m, &client.ParseError{Page: "mail source", Err: err}
 		}
 		body, err := io.ReadAll(msg.Body)
 		if err != nil {
-			return m, err
+			return m, &client.ParseError{Page: "mail source", Err: err}
 		}
+		tree := parseMIME(textproto.MIMEHeader(msg.Header), bytes.NewReader(body))
 		m = &SourceMail{
-			Headers: msg.Header,
-			Body:    string(body),
+			Headers:     decodeHeaders(msg.Header),
+			Addresses:   parseAddresses(msg.Header),
+			Body:        string(body),
+			MIME:        &tree,
+			Attachments: parseAttachments(tree),
+			Message:     message,
 		}
 	}
 	return m, nil
 }
+
+// parseHeaders returns the sender, the subject and the date
+// displayed above the content of a mail
+func parseHeaders(s *goquery.Selection) (*Sender, string, *time.Time) {
+	var sender *Sender
+	var subject string
+	var date *time.Time
+	s.Each(func(i int, s *goquery.Selection) {
+		switch i {
+		case 0:
+			subject = strings.TrimSpace(s.Text())
+		case 1:
+			sender = &Sender{}
+			sender.Name, sender.Mail = parseFrom(s.Text())
+		case 2:
+			date = parseDate(strings.Join(strings.Fields(s.Text()), " "))
+		}
+	})
+	return sender, subject, date
+}
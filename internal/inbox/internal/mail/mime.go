@@ -0,0 +1,160 @@
+package mail
+
+import (
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"strings"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// Attachment is a file attached to a mail
+type Attachment struct {
+	Index       int    `json:"index"`
+	Filename    string `json:"filename,omitempty"`
+	ContentType string `json:"contentType"`
+	Size        int    `json:"size"`
+	Content     []byte `json:"-"`
+}
+
+// Part is a node of the MIME tree of a mail, the content of the
+// leaves is decoded and the text of the text parts is converted
+// to UTF-8
+type Part struct {
+	ContentType      string `json:"contentType"`
+	Charset          string `json:"charset,omitempty"`
+	TransferEncoding string `json:"transferEncoding,omitempty"`
+	Filename         string `json:"filename,omitempty"`
+	Attachment       bool   `json:"attachment,omitempty"`
+	Size             int    `json:"size,omitempty"`
+	Text             string `json:"text,omitempty"`
+	Parts            []Part `json:"parts,omitempty"`
+	Content          []byte `json:"-"`
+}
+
+// isBody tells if a part is a text meant to be read in the mail
+func (p Part) isBody() bool {
+	return !p.Attachment && (p.ContentType == "text/plain" || p.ContentType == "text/html")
+}
+
+// leaves returns the parts holding a content in the order they appear
+func (p Part) leaves() []Part {
+	if !strings.HasPrefix(p.ContentType, "multipart/") {
+		return []Part{p}
+	}
+	leaves := []Part{}
+	for _, child := range p.Parts {
+		leaves = append(leaves, child.leaves()...)
+	}
+	return leaves
+}
+
+// parseMIME returns the MIME tree of a mail, a missing or invalid content
+// type is considered as plain text and a malformed multipart content
+// is truncated
+func parseMIME(header textproto.MIMEHeader, body io.Reader) Part {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+	p := Part{
+		ContentType:      mediaType,
+		Charset:          strings.ToLower(params["charset"]),
+		TransferEncoding: strings.ToLower(strings.TrimSpace(header.Get("Content-Transfer-Encoding"))),
+	}
+	if strings.HasPrefix(mediaType, "multipart/") {
+		r := multipart.NewReader(body, params["boundary"])
+		for {
+			child, err := r.NextRawPart()
+			if err != nil {
+				return p
+			}
+			p.Parts = append(p.Parts, parseMIME(child.Header, child))
+		}
+	}
+	p.Content, _ = io.ReadAll(decodeTransferEncoding(p.TransferEncoding, body))
+	p.Size = len(p.Content)
+	disposition, dispositionParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	p.Filename = decodeFilename(dispositionParams["filename"])
+	if p.Filename == "" {
+		p.Filename = decodeFilename(params["name"])
+	}
+	p.Attachment = disposition == "attachment" || p.Filename != "" || !strings.HasPrefix(mediaType, "text/")
+	if !p.Attachment {
+		p.Text = decodeCharset(p.Charset, p.Content)
+	}
+	return p
+}
+
+// decodeCharset converts a text to UTF-8, the invalid
+// sequences of an unknown charset are replaced
+func decodeCharset(charset string, content []byte) string {
+	if charset != "" && charset != "utf-8" && charset != "us-ascii" {
+		if e, err := htmlindex.Get(charset); err == nil {
+			if b, err := e.NewDecoder().Bytes(content); err == nil {
+				return string(b)
+			}
+		}
+	}
+	return strings.ToValidUTF8(string(content), "�")
+}
+
+// decodeFilename decodes a filename sent as an encoded-word
+// by mail clients not implementing RFC 2231
+func decodeFilename(filename string) string {
+	decoded, err := new(mime.WordDecoder).DecodeHeader(filename)
+	if err != nil {
+		return filename
+	}
+	return decoded
+}
+
+func decodeTransferEncoding(encoding string, body io.Reader) io.Reader {
+	switch encoding {
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, newlineStripper{body})
+	default:
+		return body
+	}
+}
+
+// newlineStripper removes the line breaks of a base64 content
+type newlineStripper struct {
+	r io.Reader
+}
+
+func (n newlineStripper) Read(p []byte) (int, error) {
+	l, err := n.r.Read(p)
+	j := 0
+	for _, b := range p[:l] {
+		if b != '\r' && b != '\n' {
+			p[j] = b
+			j++
+		}
+	}
+	return j, err
+}
+
+// parseAttachments returns the attachments of a MIME tree numbered from 1
+func parseAttachments(root Part) []Attachment {
+	attachments := []Attachment{}
+	for _, p := range root.leaves() {
+		if !p.Attachment {
+			continue
+		}
+		attachments = append(attachments, Attachment{
+			Index:       len(attachments) + 1,
+			Filename:    p.Filename,
+			ContentType: p.ContentType,
+			Size:        p.Size,
+			Content:     p.Content,
+		})
+	}
+	return attachments
+}
@@ -0,0 +1,72 @@
+package mail
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/antham/yogo/v4/internal/client"
+	"github.com/stretchr/testify/assert"
+)
+
+const multipartSource = `From: Shop <shop@example.com>
+To: test@yopmail.com
+Subject: Your invoice
+MIME-Version: 1.0
+Content-Type: multipart/mixed; boundary="outer"
+
+--outer
+Content-Type: multipart/related; boundary="inner"
+
+--inner
+Content-Type: text/html; charset=utf-8
+Content-Transfer-Encoding: quoted-printable
+
+<p>Your invoice is attached <img src=3D"cid:logo"></p>
+--inner
+Content-Type: image/png
+Content-Transfer-Encoding: base64
+Content-ID: <logo>
+
+iVBORw0KGgo=
+--inner--
+--outer
+Content-Type: application/pdf; name="invoice.pdf"
+Content-Disposition: attachment; filename="invoice.pdf"
+Content-Transfer-Encoding: base64
+
+JVBERi0xLjQK
+JSVFT0YK
+--outer
+Content-Type: text/plain
+Content-Disposition: attachment; filename*=UTF-8''r%C3%A9sum%C3%A9.txt
+
+Hello
+--outer
+Content-Type: text/csv; name="=?UTF-8?B?ZmFjdHVyZS5jc3Y=?="
+
+a,b
+--outer--
+`
+
+func TestParseAttachments(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<html><body><div id="mail"><pre>` + strings.ReplaceAll(multipartSource, "<", "&lt;") + `</pre></div></body></html>`))
+	assert.NoError(t, err)
+
+	m, err := Parse(client.MailSourceDoc(*doc))
+	assert.NoError(t, err)
+	assert.Equal(t, []Attachment{
+		{Index: 1, ContentType: "image/png", Size: 8, Content: []byte("\x89PNG\r\n\x1a\n")},
+		{Index: 2, Filename: "invoice.pdf", ContentType: "application/pdf", Size: 15, Content: []byte("%PDF-1.4\n%%EOF\n")},
+		{Index: 3, Filename: "résumé.txt", ContentType: "text/plain", Size: 5, Content: []byte("Hello")},
+		{Index: 4, Filename: "facture.csv", ContentType: "text/csv", Size: 3, Content: []byte("a,b")},
+	}, m.(*SourceMail).Attachments)
+
+	plain, html := m.(*SourceMail).textParts()
+	assert.Empty(t, plain)
+	assert.Equal(t, []string{`<p>Your invoice is attached <img src="cid:logo"></p>`}, html)
+
+	output, err := m.JSON()
+	assert.NoError(t, err)
+	assert.Contains(t, output, `"attachments":[{"index":1,"contentType":"image/png","size":8},{"index":2,"filename":"invoice.pdf","contentType":"application/pdf","size":15}`)
+}
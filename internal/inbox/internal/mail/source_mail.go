@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/textproto"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -14,11 +16,17 @@ import (
 	"golang.org/x/term"
 )
 
-// SourceMail is an HTML  mail message
+// SourceMail is a mail as it was received, its body is rendered as
+// a decoded MIME tree unless the raw body is requested
 type SourceMail struct {
-	ID      string              `json:"id"`
-	Headers map[string][]string `json:"headers"`
-	Body    string              `json:"body"`
+	ID          string               `json:"id"`
+	Headers     map[string][]string  `json:"headers"`
+	Addresses   map[string][]Address `json:"addresses,omitempty"`
+	Body        string               `json:"body,omitempty"`
+	MIME        *Part                `json:"mime,omitempty"`
+	Attachments []Attachment         `json:"attachments,omitempty"`
+	Raw         bool                 `json:"-"`
+	Message     string               `json:"-"`
 }
 
 func (m *SourceMail) SetID(ID string) {
@@ -48,7 +56,11 @@ func (m *SourceMail) Coloured() (string, error) {
 			info.Headers[key] = v
 		}
 	}
-	info.Body = color.CyanString(m.Body)
+	if m.Raw {
+		info.Body = color.CyanString(m.Body)
+	} else {
+		info.Body = strings.Join(colouredParts(m.mimeTree(), "1"), "\n")
+	}
 	for k, v := range info.Headers {
 		acc := []string{}
 		for i, s := range splitString(v, width-3-padding) {
@@ -76,13 +88,81 @@ func (m *SourceMail) Coloured() (string, error) {
 }
 
 func (m *SourceMail) JSON() (string, error) {
-	data, err := json.Marshal(&m)
+	mail := *m
+	if m.Raw {
+		mail.MIME = nil
+	} else {
+		tree := m.mimeTree()
+		mail.MIME = &tree
+		mail.Body = ""
+	}
+	data, err := json.Marshal(&mail)
 	if err != nil {
 		return "", errors.New("something wrong occurred")
 	}
 	return string(data), nil
 }
 
+// EML returns the mail as an RFC 5322 message, it is the message
+// displayed by yopmail with its original line endings, the headers
+// are sorted when the mail wasn't built by Parse
+func (m *SourceMail) EML() []byte {
+	if m.Message != "" {
+		return []byte(m.Message)
+	}
+	keys := make([]string, 0, len(m.Headers))
+	for k := range m.Headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		for _, v := range m.Headers[k] {
+			fmt.Fprintf(&b, "%s: %s\n", k, v)
+		}
+	}
+	return []byte(b.String() + "\n" + m.Body)
+}
+
+// mimeTree returns the MIME tree of the mail, it is parsed
+// from the raw body when the mail wasn't built by Parse
+func (m *SourceMail) mimeTree() Part {
+	if m.MIME != nil {
+		return *m.MIME
+	}
+	return parseMIME(textproto.MIMEHeader(m.Headers), strings.NewReader(m.Body))
+}
+
+// colouredParts renders a MIME tree, the parts are numbered from 1
+// and the number of a child is prefixed with the one of its parent
+func colouredParts(p Part, number string) []string {
+	details := []string{}
+	if p.Charset != "" {
+		details = append(details, "charset "+p.Charset)
+	}
+	if p.TransferEncoding != "" {
+		details = append(details, p.TransferEncoding)
+	}
+	if p.Filename != "" {
+		details = append(details, p.Filename)
+	}
+	if p.Parts == nil {
+		details = append(details, fmt.Sprintf("%d bytes", p.Size))
+	}
+	line := fmt.Sprintf("[%s] %s", number, p.ContentType)
+	if len(details) > 0 {
+		line += fmt.Sprintf(" (%s)", strings.Join(details, ", "))
+	}
+	lines := []string{color.MagentaString(line)}
+	if p.Text != "" {
+		lines = append(lines, color.CyanString(strings.TrimRight(p.Text, "\r\n")))
+	}
+	for i, child := range p.Parts {
+		lines = append(lines, colouredParts(child, fmt.Sprintf("%s.%d", number, i+1))...)
+	}
+	return lines
+}
+
 func splitString(s string, chunkSize int) []string {
 	if chunkSize >= len(s) {
 		return []string{s}
@@ -16,9 +16,10 @@ func TestSourceMail(t *testing.T) {
 
 	scenarios := []scenario{
 		{
-			name: "Display a regular email",
+			name: "Display the raw body",
 			mail: &SourceMail{
-				ID: "e_ZwZjBQRmZwZkZwR4ZQNjAQZ4ZQRlZt==",
+				Raw: true,
+				ID:  "e_ZwZjBQRmZwZkZwR4ZQNjAQZ4ZQRlZt==",
 				Headers: map[string][]string{
 					"Content-Transfer-Encoding": {"quoted-printable"},
 					"Content-Type":              {"text/html; charset=utf-8"},
@@ -76,6 +77,37 @@ TEST
 	"X-Ses-Outgoing":["2023.08.13-54.240.48.112"]
 },
 "id":"e_ZwZjBQRmZwZkZwR4ZQNjAQZ4ZQRlZt=="
+}`,
+		},
+		{
+			name: "Display the MIME tree",
+			mail: &SourceMail{
+				ID: "e_ZwZjBQRmZwZkZwR4ZQNjAQZ4ZQRlZt==",
+				Headers: map[string][]string{
+					"Content-Type": {`multipart/mixed; boundary="b"`},
+				},
+				Body: "--b\r\nContent-Type: text/plain; charset=ISO-8859-1\r\nContent-Transfer-Encoding: quoted-printable\r\n\r\nCaf=E9\r\n--b\r\nContent-Type: application/pdf\r\nContent-Disposition: attachment; filename=a.pdf\r\nContent-Transfer-Encoding: base64\r\n\r\nJVBERg==\r\n--b--\r\n",
+			},
+			outputExpected: `---
+Content-Type : multipart/mixed; boundary="b"
+
+---
+[1] multipart/mixed
+[1.1] text/plain (charset iso-8859-1, quoted-printable, 4 bytes)
+Café
+[1.2] application/pdf (base64, a.pdf, 4 bytes)
+---
+`,
+			jsonOutputExpected: `{
+"headers":{"Content-Type":["multipart/mixed; boundary=\"b\""]},
+"id":"e_ZwZjBQRmZwZkZwR4ZQNjAQZ4ZQRlZt==",
+"mime":{
+	"contentType":"multipart/mixed",
+	"parts":[
+		{"contentType":"text/plain","charset":"iso-8859-1","transferEncoding":"quoted-printable","size":4,"text":"Caf\u00e9"},
+		{"contentType":"application/pdf","transferEncoding":"base64","filename":"a.pdf","attachment":true,"size":4}
+	]
+}
 }`,
 		},
 	}
@@ -95,3 +127,36 @@ TEST
 		})
 	}
 }
+
+func TestSourceMailEML(t *testing.T) {
+	type scenario struct {
+		name     string
+		mail     *SourceMail
+		expected string
+	}
+
+	scenarios := []scenario{
+		{
+			name: "Write the message as it was received",
+			mail: &SourceMail{
+				Headers: map[string][]string{"Subject": {"Hello"}, "From": {"a@example.com"}},
+				Message: "Subject: Hello\nX-Folded: a\n\tb\r\nFrom: a@example.com\n\nline 1\nline 2",
+			},
+			expected: "Subject: Hello\nX-Folded: a\n\tb\r\nFrom: a@example.com\n\nline 1\nline 2",
+		},
+		{
+			name: "Rebuild the message from the headers and the body",
+			mail: &SourceMail{
+				Headers: map[string][]string{"Subject": {"Hello"}, "From": {"a@example.com"}, "Received": {"1", "2"}},
+				Body:    "line 1\n",
+			},
+			expected: "From: a@example.com\nReceived: 1\nReceived: 2\nSubject: Hello\n\nline 1\n",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			assert.Equal(t, s.expected, string(s.mail.EML()))
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package mail
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// TextMail is a mail rendered as plain text by yopmail
+type TextMail struct {
+	ID      string     `json:"id"`
+	Sender  *Sender    `json:"sender,omitempty"`
+	Subject string     `json:"subject,omitempty"`
+	Date    *time.Time `json:"date,omitempty"`
+	Body    string     `json:"body,omitempty"`
+}
+
+func (m *TextMail) SetID(ID string) {
+	m.ID = ID
+}
+
+func (m *TextMail) Coloured() (string, error) {
+	return colouredMail(m.Sender, m.Subject, m.Date, m.Body)
+}
+
+func (m *TextMail) JSON() (string, error) {
+	data, err := json.Marshal(&m)
+	if err != nil {
+		return "", errors.New("something wrong occurred")
+	}
+	return string(data), nil
+}
+
+// Text returns the body of the mail
+func (m *TextMail) Text() string {
+	return m.Body
+}
+
+// URLs returns the URLs written in the body of the mail
+func (m *TextMail) URLs() []string {
+	return Dedup(FindURLs(m.Body))
+}
@@ -0,0 +1,48 @@
+package mail
+
+import (
+	"testing"
+	"time"
+
+	"github.com/antham/yogo/v4/internal/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextMail(t *testing.T) {
+	date, err := time.Parse("2006-01-02 15:04", "2022-10-24 23:20")
+	assert.NoError(t, err)
+
+	m := &TextMail{ID: "test", Sender: &Sender{Name: "test", Mail: "test@protonmail.com"}, Subject: "A subject", Date: &date, Body: "line 1\nline 2 https://example.com"}
+
+	output, err := m.Coloured()
+	assert.NoError(t, err)
+	assert.Equal(t, `---
+From    : test <test@protonmail.com>
+Subject : A subject
+Date    : 2022-10-24 23:20
+---
+line 1
+line 2 https://example.com
+---
+`, output)
+
+	output, err = m.JSON()
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id":"test","sender":{"name":"test","mail":"test@protonmail.com"},"subject":"A subject","date":"2022-10-24T23:20:00Z","body":"line 1\nline 2 https://example.com"}`, output)
+
+	assert.Equal(t, m.Body, m.Text())
+	assert.Equal(t, []string{"https://example.com"}, m.URLs())
+}
+
+func TestParseTextMail(t *testing.T) {
+	m, err := Parse[client.MailTextDoc](getDoc[client.MailTextDoc](t, "text_mail.html"))
+	assert.NoError(t, err)
+
+	date := time.Date(2021, 6, 13, 20, 57, 8, 0, time.UTC)
+	assert.Equal(t, &TextMail{
+		Sender:  &Sender{Name: "Liana", Mail: "AnnaMartinezpisea@lionspest.com.au"},
+		Subject: "In any case, I am happy that we met",
+		Date:    &date,
+		Body:    "What such a gorgeous man is doing here?\nWill you come to me on the weekend?\n\nhttps://fectment.page.link/Ymry",
+	}, m)
+}
@@ -0,0 +1,19 @@
+// Package timezone provides the timezone of the dates displayed by
+// yopmail, shared by the client and the fake server
+package timezone
+
+import (
+	"time"
+	// the timezone database is embedded to load the
+	// yopmail timezone whatever the system provides
+	_ "time/tzdata"
+)
+
+// Yopmail returns the timezone of the dates displayed by yopmail
+func Yopmail() *time.Location {
+	location, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
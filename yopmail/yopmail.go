@@ -0,0 +1,429 @@
+// Package yopmail provides a Go API to read and manage yopmail inboxes.
+//
+// The exported identifiers of this package follow the semantic
+// versioning of the module: they won't change in a backward
+// incompatible way without a major version bump.
+package yopmail
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/antham/yogo/v4/internal/address"
+	"github.com/antham/yogo/v4/internal/client"
+	"github.com/antham/yogo/v4/internal/inbox"
+)
+
+// ErrCaptcha is returned when yopmail requires a CAPTCHA to be solved
+// from the web interface before serving content again
+var ErrCaptcha = client.ErrCaptcha
+
+// NetworkError is returned when a request could not be sent
+// or when its response could not be received
+type NetworkError = client.NetworkError
+
+// HTTPError is returned when yopmail answers with an error status code
+type HTTPError = client.HTTPError
+
+// ParseError is returned when a page can't be parsed,
+// yopmail has probably changed its layout
+type ParseError = client.ParseError
+
+// TokenError is returned when a value required to
+// query yopmail could not be extracted from a page
+type TokenError = client.TokenError
+
+// DomainError is returned when an inbox is named by
+// an address that is not on a yopmail alias domain
+type DomainError = address.DomainError
+
+// SendError is returned when yopmail doesn't confirm that a mail was sent
+type SendError = client.SendError
+
+// Sender defines a mail sender
+type Sender struct {
+	Mail string `json:"mail,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+// InboxItem is a mail summary as displayed in an inbox
+type InboxItem struct {
+	ID      string     `json:"id"`
+	Sender  *Sender    `json:"sender,omitempty"`
+	Subject string     `json:"subject"`
+	Date    *time.Time `json:"date,omitempty"`
+	IsSPAM  bool       `json:"isSPAM"`
+}
+
+// HTMLMail is a mail whose body is the text rendering of its HTML version
+type HTMLMail struct {
+	ID      string     `json:"id"`
+	Sender  *Sender    `json:"sender,omitempty"`
+	Subject string     `json:"subject,omitempty"`
+	Date    *time.Time `json:"date,omitempty"`
+	Body    string     `json:"body,omitempty"`
+	Links   []string   `json:"links,omitempty"`
+	IsSPAM  bool       `json:"isSPAM"`
+}
+
+// TextMail is a mail whose body is the plain text rendering made by yopmail
+type TextMail struct {
+	ID      string     `json:"id"`
+	Sender  *Sender    `json:"sender,omitempty"`
+	Subject string     `json:"subject,omitempty"`
+	Date    *time.Time `json:"date,omitempty"`
+	Body    string     `json:"body,omitempty"`
+}
+
+// Attachment is a file attached to a mail, its content is decoded
+type Attachment struct {
+	Index       int    `json:"index"`
+	Filename    string `json:"filename,omitempty"`
+	ContentType string `json:"contentType"`
+	Size        int    `json:"size"`
+	Content     []byte `json:"-"`
+}
+
+// Part is a node of the MIME tree of a mail, the content of the
+// leaves is decoded and the text of the text parts is converted
+// to UTF-8
+type Part struct {
+	ContentType      string `json:"contentType"`
+	Charset          string `json:"charset,omitempty"`
+	TransferEncoding string `json:"transferEncoding,omitempty"`
+	Filename         string `json:"filename,omitempty"`
+	Attachment       bool   `json:"attachment,omitempty"`
+	Size             int    `json:"size,omitempty"`
+	Text             string `json:"text,omitempty"`
+	Parts            []Part `json:"parts,omitempty"`
+	Content          []byte `json:"-"`
+}
+
+// Address is a name and an address parsed from a mail header
+type Address struct {
+	Name string `json:"name,omitempty"`
+	Mail string `json:"mail"`
+}
+
+// SourceMail is a mail as it was received by yopmail, Body
+// is the raw body and MIME the decoded one, the encoded words
+// of the headers are decoded and the address headers (From, To,
+// Cc and Reply-To) are parsed into Addresses
+type SourceMail struct {
+	ID          string               `json:"id"`
+	Headers     map[string][]string  `json:"headers"`
+	Addresses   map[string][]Address `json:"addresses,omitempty"`
+	Body        string               `json:"body"`
+	MIME        *Part                `json:"mime,omitempty"`
+	Attachments []Attachment         `json:"attachments,omitempty"`
+	// EML is the RFC 5322 message, its headers are in
+	// their original order and its line endings unchanged
+	EML []byte `json:"-"`
+}
+
+// RetryPolicy defines how requests failing because of a transient
+// error (network failure, 429 or 5xx status) are attempted again
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts made for a request
+	MaxAttempts int
+	// BaseDelay is the delay before the second attempt, it doubles
+	// on every following attempt
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between two attempts,
+	// including the one requested with Retry-After
+	MaxDelay time.Duration
+}
+
+// RateLimit defines the pace at which requests are sent to yopmail,
+// the pace slows down automatically when yopmail shows signs of
+// throttling
+type RateLimit struct {
+	// RequestsPerMinute is the sustained rate, 0 disables the limiter
+	RequestsPerMinute int
+	// Burst is the number of requests that can be sent at once
+	Burst int
+}
+
+// CaptchaPolicy defines how a client waits for a CAPTCHA to be lifted
+// and resumes the interrupted operation instead of failing
+type CaptchaPolicy struct {
+	// Cooldown is the delay before probing yopmail for the first
+	// time, the wait is enabled when it is greater than 0
+	Cooldown time.Duration
+	// ProbeInterval is the delay between two probes,
+	// it defaults to the cooldown
+	ProbeInterval time.Duration
+	// Deadline caps the overall time spent waiting, 0 waits
+	// until the context is done
+	Deadline time.Duration
+	// Progress receives a line on every step of the wait, may be nil
+	Progress io.Writer
+}
+
+// Client gives access to yopmail inboxes, an inbox is named by its
+// login or by its address on any of the yopmail alias domains
+type Client struct {
+	html   client.Client[client.MailHTMLDoc]
+	text   client.Client[client.MailTextDoc]
+	source client.Client[client.MailSourceDoc]
+}
+
+// Option customizes a Client
+type Option func(*client.Config)
+
+// WithDebug logs all requests and responses on stderr,
+// cookies and session tokens are redacted
+func WithDebug(enable bool) Option {
+	return func(c *client.Config) {
+		c.EnableDebugMode = enable
+	}
+}
+
+// WithLogger sends the debug records of the requests and
+// responses to a logger, they are emitted at the debug level
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *client.Config) {
+		c.Logger = logger
+	}
+}
+
+// WithRetry retries requests failing because of a transient error,
+// requests are not retried by default
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *client.Config) {
+		c.Retry = client.RetryPolicy(policy)
+	}
+}
+
+// WithRateLimit paces the requests sent to yopmail to not trigger a
+// CAPTCHA, requests are limited to 30 per minute with a burst of 5
+// by default, a zero RateLimit disables the limit
+func WithRateLimit(limit RateLimit) Option {
+	return func(c *client.Config) {
+		c.RateLimit = client.RateLimit(limit)
+	}
+}
+
+// WithCaptchaWait waits for a CAPTCHA to be lifted, by default
+// ErrCaptcha is returned as soon as yopmail requires one
+func WithCaptchaWait(policy CaptchaPolicy) Option {
+	return func(c *client.Config) {
+		c.Captcha = client.CaptchaPolicy(policy)
+	}
+}
+
+// WithBaseURL targets another server than yopmail,
+// a fake server run with "yogo fake-server" for instance
+func WithBaseURL(URL string) Option {
+	return func(c *client.Config) {
+		c.BaseURL = URL
+	}
+}
+
+// WithTimezone sets the timezone of the times displayed in
+// the inboxes, the dates of InboxItem are built in it, it
+// is Europe/Paris by default
+func WithTimezone(location *time.Location) Option {
+	return func(c *client.Config) {
+		c.Location = location
+	}
+}
+
+// WithSessionFile persists the session tokens and the cookies in a
+// file shared between runs, the session is kept in memory by default
+func WithSessionFile(path string) Option {
+	return func(c *client.Config) {
+		c.SessionFile = path
+	}
+}
+
+// New creates a new client
+func New(ctx context.Context, opts ...Option) (*Client, error) {
+	config := client.Config{RateLimit: client.DefaultRateLimit()}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	c, err := client.New[client.MailHTMLDoc](ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{html: c, text: client.Convert[client.MailTextDoc](c), source: client.Convert[client.MailSourceDoc](c)}, nil
+}
+
+// List returns at most limit mails from an inbox, the most recent first
+func (c *Client) List(ctx context.Context, name string, limit int) ([]InboxItem, error) {
+	login, err := normalize(name)
+	if err != nil {
+		return nil, err
+	}
+	in := inbox.NewInboxWithClient(login, c.html)
+	if err := in.ParseInboxPages(ctx, limit); err != nil {
+		return nil, err
+	}
+	items := make([]InboxItem, 0, in.Count())
+	for _, i := range in.GetMails() {
+		item := InboxItem{
+			ID:      i.ID,
+			Subject: i.Subject,
+			Date:    i.Date,
+			IsSPAM:  i.IsSPAM,
+		}
+		if i.Sender != nil {
+			item.Sender = &Sender{Mail: i.Sender.Mail, Name: i.Sender.Name}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// GetHTMLMail fetches a mail from its identifier
+func (c *Client) GetHTMLMail(ctx context.Context, name string, ID string) (*HTMLMail, error) {
+	login, err := normalize(name)
+	if err != nil {
+		return nil, err
+	}
+	r, err := inbox.NewInboxWithClient(login, c.html).FetchByID(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := r.(*inbox.HTMLMail)
+	if !ok {
+		return nil, errors.New("unexpected mail type")
+	}
+	mail := &HTMLMail{
+		ID:      m.ID,
+		Subject: m.Subject,
+		Date:    m.Date,
+		Body:    m.Body,
+		Links:   m.Links,
+		IsSPAM:  m.IsSPAM,
+	}
+	if m.Sender != nil {
+		mail.Sender = &Sender{Mail: m.Sender.Mail, Name: m.Sender.Name}
+	}
+	return mail, nil
+}
+
+// GetTextMail fetches the plain text rendering of a mail from its identifier
+func (c *Client) GetTextMail(ctx context.Context, name string, ID string) (*TextMail, error) {
+	login, err := normalize(name)
+	if err != nil {
+		return nil, err
+	}
+	r, err := inbox.NewInboxWithClient(login, c.text).FetchByID(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := r.(*inbox.TextMail)
+	if !ok {
+		return nil, errors.New("unexpected mail type")
+	}
+	mail := &TextMail{
+		ID:      m.ID,
+		Subject: m.Subject,
+		Date:    m.Date,
+		Body:    m.Body,
+	}
+	if m.Sender != nil {
+		mail.Sender = &Sender{Mail: m.Sender.Mail, Name: m.Sender.Name}
+	}
+	return mail, nil
+}
+
+// GetSourceMail fetches the source of a mail from its identifier
+func (c *Client) GetSourceMail(ctx context.Context, name string, ID string) (*SourceMail, error) {
+	login, err := normalize(name)
+	if err != nil {
+		return nil, err
+	}
+	r, err := inbox.NewInboxWithClient(login, c.source).FetchByID(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := r.(*inbox.SourceMail)
+	if !ok {
+		return nil, errors.New("unexpected mail type")
+	}
+	mail := &SourceMail{ID: m.ID, Headers: m.Headers, Body: m.Body, EML: m.EML()}
+	if m.MIME != nil {
+		p := newPart(*m.MIME)
+		mail.MIME = &p
+	}
+	for k, as := range m.Addresses {
+		if mail.Addresses == nil {
+			mail.Addresses = map[string][]Address{}
+		}
+		for _, a := range as {
+			mail.Addresses[k] = append(mail.Addresses[k], Address(a))
+		}
+	}
+	for _, a := range m.Attachments {
+		mail.Attachments = append(mail.Attachments, Attachment(a))
+	}
+	return mail, nil
+}
+
+func newPart(p inbox.Part) Part {
+	part := Part{
+		ContentType:      p.ContentType,
+		Charset:          p.Charset,
+		TransferEncoding: p.TransferEncoding,
+		Filename:         p.Filename,
+		Attachment:       p.Attachment,
+		Size:             p.Size,
+		Text:             p.Text,
+		Content:          p.Content,
+	}
+	for _, child := range p.Parts {
+		part.Parts = append(part.Parts, newPart(child))
+	}
+	return part
+}
+
+// Domains fetches the yopmail alias domains,
+// all of them deliver to the same inboxes
+func (c *Client) Domains(ctx context.Context) ([]string, error) {
+	return c.html.GetDomains(ctx)
+}
+
+// Delete removes a mail from an inbox
+func (c *Client) Delete(ctx context.Context, name string, ID string) error {
+	login, err := normalize(name)
+	if err != nil {
+		return err
+	}
+	return c.html.DeleteMail(ctx, login, ID)
+}
+
+// Send sends a mail from an inbox, yopmail only
+// delivers it when the recipient is a yopmail address
+func (c *Client) Send(ctx context.Context, name string, to string, subject string, body string) error {
+	login, from, err := address.Normalize(name, client.DefaultDomains())
+	if err != nil {
+		return err
+	}
+	return c.html.SendMail(ctx, login, from, to, subject, body)
+}
+
+// Flush removes all mails from an inbox
+func (c *Client) Flush(ctx context.Context, name string) error {
+	login, err := normalize(name)
+	if err != nil {
+		return err
+	}
+	in := inbox.NewInboxWithClient(login, c.html)
+	if err := in.ParseInboxPages(ctx, 1); err != nil {
+		return err
+	}
+	return in.Flush(ctx)
+}
+
+// normalize returns the login of an inbox from its name
+// or from its address on a default yopmail alias domain
+func normalize(name string) (string, error) {
+	login, _, err := address.Normalize(name, client.DefaultDomains())
+	return login, err
+}
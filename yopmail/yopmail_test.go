@@ -0,0 +1,116 @@
+package yopmail
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+const inboxPage1URL = "https://yopmail.com/en/inbox?ad=0&ctrl=&d=&id=&login=test&p=1&r_c=&scrl=&spam=true&v=4.8&yj=VZGV5AmpjZwp5ZGNmZwL0BQH&yp=UAQDkAGH2Amp2Zmt0ZmVmAGp"
+
+func TestClient(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	assert.NoError(t, registerResponders([]responder{
+		{"GET", "https://yopmail.com", "../internal/inbox/features/main_page.html"},
+		{"GET", "https://yopmail.com/ver/4.8/webmail.js", "../internal/inbox/features/webmail.js"},
+		{"GET", inboxPage1URL, "../internal/inbox/features/inbox_page_1.html"},
+		{"GET", "https://yopmail.com/en/mail?b=test&id=me_ZwRjAwRmZGtmAwZ1ZQNjAwt5AQZmZj%3D%3D", "../internal/inbox/features/mail.html"},
+		{"GET", "https://yopmail.com/en/mail?b=test&id=te_ZwRjAwRmZGtmAwZ1ZQNjAwt5AQZmZj%3D%3D", "../internal/inbox/internal/mail/features/text_mail.html"},
+		{"GET", "https://yopmail.com/en/mail?b=test&id=se_ZwRjAwRmZGtmAwZ1ZQNjAwt5AQZmZj%3D%3D", "../internal/inbox/internal/mail/features/source_mail.html"},
+		{"GET", "https://yopmail.com/en/inbox?ad=0&ctrl=&d=e_ZwRjAwRmZGtmAwZ1ZQNjAwt5AQZmZj%3D%3D&id=&login=test&p=1&r_c=&v=4.8&yj=VZGV5AmpjZwp5ZGNmZwL0BQH&yp=UAQDkAGH2Amp2Zmt0ZmVmAGp", "../internal/inbox/features/noop.html"},
+		{"POST", "https://yopmail.com/en/writepost?v=4.8&yj=VZGV5AmpjZwp5ZGNmZwL0BQH&yp=UAQDkAGH2Amp2Zmt0ZmVmAGp", "../internal/inbox/features/sent.html"},
+		{"GET", "https://yopmail.com/en/inbox?ad=0&ctrl=e_ZwRjAwRmZGtmAwZ1ZQNjAwt5AQZmZj%3D%3D&d=all&id=&login=test&p=1&r_c=&v=4.8&yj=VZGV5AmpjZwp5ZGNmZwL0BQH&yp=UAQDkAGH2Amp2Zmt0ZmVmAGp", "../internal/inbox/features/inbox_empty.html"},
+	}))
+
+	ctx := context.Background()
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	assert.NoError(t, err)
+	c, err := New(ctx, WithTimezone(tokyo), WithRateLimit(RateLimit{}))
+	assert.NoError(t, err)
+
+	items, err := c.List(ctx, "test", 2)
+	assert.NoError(t, err)
+	assert.Len(t, items, 2)
+	assert.Equal(t, "e_ZwRjAwRmZGtmAwZ1ZQNjAwt5AQZmZj==", items[0].ID)
+	assert.Equal(t, &Sender{Name: "Lilliana"}, items[0].Sender)
+	assert.Equal(t, "I need help", items[0].Subject)
+	assert.Equal(t, "20:36", items[0].Date.Format("15:04"))
+	assert.Equal(t, tokyo, items[0].Date.Location())
+
+	html, err := c.GetHTMLMail(ctx, "test", items[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, items[0].ID, html.ID)
+	assert.NotEmpty(t, html.Body)
+
+	text, err := c.GetTextMail(ctx, "Test@yopmail.fr", items[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, items[0].ID, text.ID)
+	assert.Equal(t, &Sender{Name: "Liana", Mail: "AnnaMartinezpisea@lionspest.com.au"}, text.Sender)
+	assert.Contains(t, text.Body, "Will you come to me on the weekend?")
+
+	source, err := c.GetSourceMail(ctx, "test", items[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, items[0].ID, source.ID)
+	assert.Equal(t, []string{"test@yopmail.com"}, source.Headers["To"])
+	assert.Equal(t, []Address{{Mail: "test@yopmail.com"}}, source.Addresses["To"])
+	assert.Equal(t, "text/html", source.MIME.ContentType)
+	assert.Contains(t, source.MIME.Text, "Marcación")
+	assert.Contains(t, string(source.EML), "To: test@yopmail.com\n")
+
+	assert.NoError(t, c.Delete(ctx, "test@yopmail.com", items[0].ID))
+	assert.NoError(t, c.Send(ctx, "test", "test2@yopmail.com", "subject", "body"))
+
+	httpmock.RegisterResponder("GET", "https://yopmail.com/en/domain?d=all", httpmock.NewStringResponder(200, "<select><option>@yopmail.com</option><option>@yopmail.fr</option></select>"))
+	domains, err := c.Domains(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"yopmail.com", "yopmail.fr"}, domains)
+	assert.NoError(t, c.Flush(ctx, "test"))
+	assert.Equal(t, 1, httpmock.GetCallCountInfo()["GET https://yopmail.com"])
+
+	_, err = c.List(ctx, "test@example.com", 1)
+	var domainErr *DomainError
+	assert.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "example.com", domainErr.Domain)
+}
+
+func TestClientErrors(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "https://yopmail.com", httpmock.NewStringResponder(404, "not found"))
+	_, err := New(context.Background())
+	var HTTPErr *HTTPError
+	assert.ErrorAs(t, err, &HTTPErr)
+	assert.Equal(t, 404, HTTPErr.StatusCode)
+
+	httpmock.RegisterResponder("GET", "https://yopmail.com", httpmock.NewStringResponder(200, "<html></html>"))
+	_, err = New(context.Background())
+	var tokenErr *TokenError
+	assert.ErrorAs(t, err, &tokenErr)
+	assert.Equal(t, "api version", tokenErr.Token)
+}
+
+type responder struct {
+	method   string
+	URL      string
+	filename string
+}
+
+func registerResponders(responders []responder) error {
+	for _, r := range responders {
+		b, err := os.ReadFile(r.filename)
+		if err != nil {
+			return err
+		}
+
+		httpmock.RegisterResponder(r.method, r.URL,
+			httpmock.NewStringResponder(200, string(b)))
+	}
+	return nil
+}